@@ -1,16 +1,13 @@
 package archive
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 
 	"github.com/raynaluzier/artifactory-go-sdk/common"
 	"github.com/raynaluzier/artifactory-go-sdk/operations"
-	"github.com/raynaluzier/artifactory-go-sdk/util"
 )
 
 type Contents struct {
@@ -18,157 +15,89 @@ type Contents struct {
 	IsFolder		bool
 }
 
-type artifJson struct {
-	Repo			string 	`json:"repo"`
-	Path			string	`json:"path"`
-	Created			string	`json:"created"`
-	CreatedBy		string	`json:"createdBy"`
-	LastModified	string	`json:"lastModified"`
-	ModifiedBy		string	`json:"modifiedBy"`
-	LastUpdated		string	`json:"lastUpdated"`
-	DownloadUri 	string 	`json:"downloadUri"`
-	MimeType 		string	`json:"mimeType"`
-	Size			string	`json:"size"`
-	Checksums	struct {
-		Sha1		string	`json:"sha1"`
-		Md5			string	`json:"md5"`
-		Sha256		string	`json:"sha256"`
-	}	`json:"checksums"`
-	OriginalChecksums	struct {
-		Sha1		string	`json:"sha1"`
-		Md5			string	`json:"md5"`
-		Sha256		string	`json:"sha256"`				
-	}   `json:"originalChecksums"`
-	Uri 			string	`json:"uri"`
-}
-
 var request *http.Request
 var err error
-var foundPaths []string
 
 func GetItemChildren(item string) ([]Contents, error) {
 	// Item can represent a repo name or a combo of repo/child_folder/subchild_folder/etc
-	bearer := common.SetBearer(util.Token)
-	requestPath := util.ServerApi + "/storage/" + item
+	// The item's children are taken from operations.GetItemInfo
 	common.LogTxtHandler().Info(">>> Getting Item Children for Item" + item + "...")
-
-	type itemResults struct {
-		Repo			string		`json:"repo"`
-		Path			string		`json:"path"`
-		Created			string		`json:"created"`
-		LastModified 	string		`json:"lastModified"`
-		LastUpdated		string		`json:"lastUpdated"`
-		Children []struct {
-			Uri		string		`json:"uri"`
-			Folder	bool		`json:"folder"`
-		}
-		Uri				string		`json:"uri"`
-		CreatedBy		string		`json:"createdBy"`	// Not exposed at repo level
-		ModifiedBy		string		`json:"modifiedBy"`	// Not exposed at repo level
-	}
-
 	var childDetails []Contents
 
-	if (item != "") {
-		common.LogTxtHandler().Debug("REQUEST: Sending 'GET' request to: " + requestPath)
-		request, err = http.NewRequest("GET", requestPath, nil)
-		request.Header.Add("Authorization", bearer)
-
-		client := &http.Client{}
-		response, err := client.Do(request)
-		if err != nil {
-			strErr := fmt.Sprintf("%v\n", err)
-			common.LogTxtHandler().Error("Error on response. " + strErr)
-			return nil, err
-
-		} else {
-			defer response.Body.Close()
-			body, err := io.ReadAll(response.Body)
-			common.LogTxtHandler().Debug("REQUEST RESPONSE: " + string(body))
-
-			var jsonData *itemResults
-			err = json.Unmarshal(body, &jsonData)
-			if err != nil {
-				strErr := fmt.Sprintf("%v\n", err)
-				common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
-			}
-
-			// If the item has children, parse the data and return the abbreviated
-			// URI ('/folder', '/folder/artifact.ext', etc) and whether the child item is a folder or not (bool)
-			if len(jsonData.Children) != 0 {
-				common.LogTxtHandler().Debug("CHILD OBJECTS FOUND FOR: " + item)
-				for idx, c := range jsonData.Children {
-					c = jsonData.Children[idx]
-					childDetails = append(childDetails, Contents{Child: c.Uri, IsFolder: c.Folder})
-
-					strIsFolder := fmt.Sprintf("%v\n", c.Folder)
-					common.LogTxtHandler().Debug("CHILD: " + c.Uri + " - IS FOLDER: " + strIsFolder)
-				}
-				return childDetails, nil
-
-			} else {
-				// If no children found, we return empty contents; this isn't an error condition
-				common.LogTxtHandler().Warn("No child objects found for " + item)
-				return childDetails, nil
-			}
-
-			/*
-			for idx := 0; idx < len(childDetails); idx++ {
-				fmt.Println(childDetails[idx].Child, childDetails[idx].IsFolder)
-			}*/
-			// ex: [{/test-artifact-1.1.txt false} {/test-artifact-1.2.txt false} {/test-artifact-1.3.txt false}] <nil>
-			}
-	} else {
+	if item == "" {
 		err := errors.New("No item or path provided. Unable to get child items without parent item/path.")
 		common.LogTxtHandler().Error("No item or path provided. Unable to get child items without parent item/path.")
 		return nil, err
 	}
+
+	info, err := operations.GetItemInfo("/" + strings.Trim(item, "/"))
+	if err != nil && !errors.Is(err, operations.ErrItemNotFound) {
+		return nil, err
+	}
+
+	// If the item has children, return the abbreviated URI ('/folder', '/folder/artifact.ext', etc) and whether
+	// the child item is a folder or not (bool)
+	if len(info.Children) == 0 {
+		// If no children found (or the item doesn't exist), we return empty contents; this isn't an error condition
+		common.LogTxtHandler().Warn("No child objects found for " + item)
+		return childDetails, nil
+	}
+	common.LogTxtHandler().Debug("CHILD OBJECTS FOUND FOR: " + item)
+	for _, c := range info.Children {
+		childDetails = append(childDetails, Contents{Child: c.Child, IsFolder: c.IsFolder})
+		common.LogTxtHandler().Debug("CHILD: " + c.Child + " - IS FOLDER: " + fmt.Sprintf("%v", c.IsFolder))
+	}
+	return childDetails, nil
 }
 
 func GetArtifactPath(artifName string) ([]string, error) {
 	// Takes in an artifact's name and searches Artifactory, returning the path to the artifact
-	// Searches are CASE SENSITIVE; so RecursiveSearch does upper and lowercase checks as well
-	var childList []Contents
+	// Searches are CASE SENSITIVE; so the name is checked as given, and in upper and lowercase as well
+	// Each repo is walked with operations.WalkTree, so repos are no longer searched folder by folder; only names are
+	// needed, so no per-file metadata is requested
 	var listOfPaths []string
-	foundPaths = nil
 	common.LogTxtHandler().Info(">>> Getting Artifact Path for Artifact " + artifName + "...")
 
 	if artifName != "" {
 		listRepos, err := operations.ListRepos()
-		if err != nil || listRepos[0] == "" || len(listRepos) == 0 {
+		if err != nil || len(listRepos) == 0 || listRepos[0] == "" {
 			err := errors.New("No repos found.")
 			common.LogTxtHandler().Warn("No repos found.")
 			return nil, err
 		}
 
-		if len(listRepos) != 0 {
-			for idx := 0; idx < len(listRepos); idx++ {
-				childList, err = GetItemChildren(listRepos[idx])
-				if len(childList) != 0 {
-					listOfPaths = RecursiveSearch(childList, artifName, listRepos[idx], foundPaths)
+		lowStr := common.ConvertToLowercase(artifName)
+		upStr := common.ConvertToUppercase(artifName)
+		for idx := 0; idx < len(listRepos); idx++ {
+			err = operations.WalkTree(listRepos[idx], operations.WalkOptions{SkipFileInfo: true}, func(entry operations.WalkEntry) error {
+				if !entry.Folder && (strings.Contains(entry.Name, artifName) || strings.Contains(entry.Name, lowStr) || strings.Contains(entry.Name, upStr)) {
+					searchPath := strings.TrimPrefix(entry.Path[:strings.LastIndex(entry.Path, "/")], "/")   // "repo/folder"
+					common.LogTxtHandler().Debug("Found possible path: " + searchPath)
+					listOfPaths = append(listOfPaths, searchPath)
 				}
+				return nil
+			})
+			if err != nil {
+				strErr := fmt.Sprintf("%v\n", err)
+				common.LogTxtHandler().Warn("Unable to search repo: " + listRepos[idx] + " - " + strErr)
 			}
+		}
 
+		if len(listOfPaths) > 1 {
+			// We'll search the list for duplicates and remove them
+			common.LogTxtHandler().Info("Removing duplicate paths...")
+			listOfPaths = common.RemoveDuplicateStrings(listOfPaths)
 			if len(listOfPaths) > 1 {
-				// We'll search the list for duplicates and remove them
-				common.LogTxtHandler().Info("Removing duplicate paths...")
-				listOfPaths = common.RemoveDuplicateStrings(listOfPaths)
-				if len(listOfPaths) > 1 {
-					common.LogTxtHandler().Info("More than one possible artifact path found.")
-				}
-				return listOfPaths, nil
+				common.LogTxtHandler().Info("More than one possible artifact path found.")
+			}
+			return listOfPaths, nil
 
-			} else if len(listOfPaths) == 1 && listOfPaths[0] != "" {
-				return listOfPaths, nil
+		} else if len(listOfPaths) == 1 {
+			return listOfPaths, nil
 
-			} else if len(listOfPaths) == 0 || listOfPaths[0] == "" {
-				err := errors.New("Unable to find path to artifact.")
-				common.LogTxtHandler().Error("Unable to find path to artifact.")
-				return nil, err
-			}
 		} else {
-			err := errors.New("List of repos to check is empty. Either there are no repos or you do not have sufficient permissions to the repo(s).")
-			common.LogTxtHandler().Error("List of repos to check is empty. Either there are no repos or you do not have sufficient permissions to the repo(s).")
+			err := errors.New("Unable to find path to artifact.")
+			common.LogTxtHandler().Error("Unable to find path to artifact.")
 			return nil, err
 		}
 	} else {
@@ -176,11 +105,9 @@ func GetArtifactPath(artifName string) ([]string, error) {
 		common.LogTxtHandler().Error("Unable to determine path to artifact without the artifact name.")
 		return nil, err
 	}
-
-	// Now we have a list of paths to check through... can do another search for props... ** FINISH THIS
-	return listOfPaths, err
 }
 
+// Deprecated: RecursiveSearch walks one folder at a time; use operations.WalkTree instead
 func RecursiveSearch(list []Contents, artifName, searchPath string, foundPaths []string) ([]string) {
 	// Recursively searches a list of child items for the specificied artifact name 
 	var nextList []Contents
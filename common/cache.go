@@ -0,0 +1,293 @@
+package common
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// Responses larger than this aren't cached, so deep listings of large repos don't crowd out everything else
+const maxCachedBodySize = 4 << 20
+
+// Endpoints (relative to the API server) whose responses may be cached; downloads are never cached
+var cacheableEndpoints = []string{"/search/", "/storage/"}
+
+// CacheStats are the response cache's counters since it was enabled (or last reset)
+type CacheStats struct {
+	Hits			int64
+	Misses			int64
+	Evictions		int64
+	Invalidations	int64
+	Entries			int
+}
+
+type cacheEntry struct {
+	key			string
+	body		[]byte
+	header		http.Header
+	expires		time.Time
+}
+
+type responseCache struct {
+	mu			sync.Mutex
+	enabled		bool
+	maxEntries	int
+	defaultTTL	time.Duration
+	ttls		map[string]time.Duration
+	entries		map[string]*list.Element
+	order		*list.List		// Most recently used at the front
+	stats		CacheStats
+	generation	uint64			// Bumped on every invalidation; responses to requests from an older generation aren't stored
+}
+
+var cache = &responseCache{ttls: map[string]time.Duration{}}
+
+// Set on a request's context by SendRequestNoCache so the transport skips the cache for it
+type noCacheKey struct{}
+
+type cachingTransport struct {
+	base		http.RoundTripper
+}
+
+func NewClient() *http.Client {
+	// Returns the HTTP client used for all Artifactory requests
+	// When the response cache is enabled (see EnableCache), reads are served from it and writes invalidate it
+	return &http.Client{Transport: cachingTransport{base: http.DefaultTransport}}
+}
+
+func EnableCache(maxEntries int, defaultTTL time.Duration) {
+	// Turns on the in-process cache for search, item info, and AQL responses
+	// At most maxEntries responses are kept; the least recently used are evicted first
+	// defaultTTL applies to any endpoint without its own TTL (see SetCacheTTL)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.enabled = maxEntries > 0 && defaultTTL > 0
+	cache.maxEntries = maxEntries
+	cache.defaultTTL = defaultTTL
+	cache.entries = make(map[string]*list.Element)
+	cache.order = list.New()
+	cache.stats = CacheStats{}
+	LogTxtHandler().Debug("Response cache enabled: " + defaultTTL.String() + " default TTL")
+}
+
+func DisableCache() {
+	// Turns off the response cache and drops everything in it
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.enabled = false
+	cache.entries = nil
+	cache.order = nil
+}
+
+func SetCacheTTL(endpoint string, ttl time.Duration) {
+	// Sets the TTL for one endpoint, given as a path relative to the API server (ex: '/search/artifact', '/storage')
+	// The longest matching endpoint wins; a TTL of 0 means that endpoint's responses aren't cached
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.ttls["/" + strings.Trim(endpoint, "/")] = ttl
+}
+
+func InvalidateCache() {
+	// Drops every cached response; done automatically after any write request
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.invalidate()
+}
+
+func GetCacheStats() CacheStats {
+	// Returns the cache's hit/miss/eviction/invalidation counts and current number of entries
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	stats := cache.stats
+	if cache.order != nil {
+		stats.Entries = cache.order.Len()
+	}
+	return stats
+}
+
+func (s CacheStats) HitRate() float64 {
+	// Fraction of cacheable requests that were served from the cache
+	if s.Hits + s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits + s.Misses)
+}
+
+func (c *responseCache) invalidate() {
+	// Any read still in flight started before this, so its response must not be stored
+	c.generation++
+	if !c.enabled || c.order.Len() == 0 {
+		return
+	}
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.stats.Invalidations++
+	LogTxtHandler().Debug("Response cache invalidated.")
+}
+
+func (c *responseCache) ttlFor(request *http.Request) time.Duration {
+	// Reads are cacheable if they're to a search or storage endpoint; AQL queries are POSTs but don't change anything
+	endpoint, found := strings.CutPrefix(request.URL.String(), TrimEndSlashUrl(util.ServerApi))
+	if !found {
+		return 0
+	}
+	endpoint, _, _ = strings.Cut(endpoint, "?")
+	if request.Method == http.MethodPost && endpoint != "/search/aql" {
+		return 0
+	}
+	if request.Method != http.MethodGet && request.Method != http.MethodPost {
+		return 0
+	}
+	cacheable := false
+	for _, prefix := range cacheableEndpoints {
+		if strings.HasPrefix(endpoint, prefix) {
+			cacheable = true
+		}
+	}
+	if !cacheable {
+		return 0
+	}
+
+	// Longest matching endpoint TTL wins
+	var prefixes []string
+	for prefix := range c.ttls {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+	for _, prefix := range prefixes {
+		if endpoint == prefix || strings.HasPrefix(endpoint, prefix + "/") || strings.HasPrefix(endpoint, prefix + "?") {
+			return c.ttls[prefix]
+		}
+	}
+	return c.defaultTTL
+}
+
+func cacheKey(request *http.Request) (string, error) {
+	// Responses depend on the URL, the token (permissions), the result detail requested, and for AQL, the query
+	key := request.Method + " " + request.URL.String() + "\n" + request.Header.Get("Authorization") + "\n" + request.Header.Get("X-Result-Detail")
+	if request.GetBody != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return "", err
+		}
+		key = key + "\n" + string(data)
+	}
+	return key, nil
+}
+
+func (t cachingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	cache.mu.Lock()
+	enabled := cache.enabled
+	ttl := time.Duration(0)
+	if enabled {
+		ttl = cache.ttlFor(request)
+	}
+	cache.mu.Unlock()
+
+	if !enabled {
+		return t.base.RoundTrip(request)
+	}
+	if noCache, _ := request.Context().Value(noCacheKey{}).(bool); noCache {
+		ttl = 0
+	}
+	if ttl <= 0 {
+		write := request.Method != http.MethodGet && request.Method != http.MethodHead && !strings.HasSuffix(request.URL.Path, "/search/aql")
+		if write {
+			// Anything that may have changed an artifact, property, or repo invalidates all cached reads, both before it's
+			// sent (reads already in flight may return the old state) and after it's done
+			InvalidateCache()
+		}
+		response, err := t.base.RoundTrip(request)
+		if write {
+			InvalidateCache()
+		}
+		return response, err
+	}
+
+	key, err := cacheKey(request)
+	if err != nil {
+		return t.base.RoundTrip(request)
+	}
+
+	cache.mu.Lock()
+	if elem, found := cache.entries[key]; found {
+		entry := elem.Value.(*cacheEntry)
+		if time.Now().Before(entry.expires) {
+			cache.order.MoveToFront(elem)
+			cache.stats.Hits++
+			cache.mu.Unlock()
+			LogTxtHandler().Debug("CACHE HIT: " + request.URL.String())
+			return &http.Response{
+				Status:			"200 OK",
+				StatusCode:		http.StatusOK,
+				Proto:			"HTTP/1.1",
+				ProtoMajor:		1,
+				ProtoMinor:		1,
+				Header:			entry.header.Clone(),
+				Body:			io.NopCloser(bytes.NewReader(entry.body)),
+				ContentLength:	int64(len(entry.body)),
+				Request:		request,
+			}, nil
+		}
+		cache.order.Remove(elem)
+		delete(cache.entries, key)
+	}
+	cache.stats.Misses++
+	generation := cache.generation
+	cache.mu.Unlock()
+
+	response, err := t.base.RoundTrip(request)
+	if err != nil || response.StatusCode != http.StatusOK {
+		return response, err
+	}
+	if response.ContentLength > maxCachedBodySize {
+		return response, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxCachedBodySize + 1))
+	if err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedBodySize {
+		// Too large to cache; hand back what was read followed by the rest of the body
+		response.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), response.Body), response.Body}
+		return response, nil
+	}
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if !cache.enabled || cache.generation != generation {
+		return response, nil
+	}
+	if elem, found := cache.entries[key]; found {
+		cache.order.Remove(elem)
+	}
+	cache.entries[key] = cache.order.PushFront(&cacheEntry{key: key, body: body, header: response.Header.Clone(), expires: time.Now().Add(ttl)})
+	for cache.order.Len() > cache.maxEntries {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*cacheEntry).key)
+		cache.stats.Evictions++
+	}
+	return response, nil
+}
@@ -2,17 +2,27 @@ package common
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/raynaluzier/artifactory-go-sdk/util"
 )
@@ -108,6 +118,21 @@ func FormatServerForDownloadUri(serverApi string) string {
 	}
 }
 
+func TrimApiFromServer(serverApi string) (string, error) {
+	// Returns the server URL without the trailing '/api' (ex: http://server.com:8081/artifactory), which is where
+	// files and folders are deployed and deleted; uses util.ServerApi if blank
+	if serverApi == "" {
+		serverApi = util.ServerApi
+	}
+	trimmedBase, found := strings.CutSuffix(TrimEndSlashUrl(serverApi), "/api")
+	if !found || trimmedBase == "" {
+		err := errors.New("Server API '" + serverApi + "' must be the Artifactory API URL (ex: http://server.com:8081/artifactory/api).")
+		LogTxtHandler().Error("Server API '" + serverApi + "' must be the Artifactory API URL (ex: http://server.com:8081/artifactory/api).")
+		return "", err
+	}
+	return trimmedBase, nil
+}
+
 func SearchForExactString(searchTerm, inputStr string) (bool, error) {
 	// For example: "win2022" will return true if input string is "win2022", false if "win2022-iis"
 	result, err := regexp.MatchString("(?sm)^" + searchTerm + "$", inputStr)
@@ -379,7 +404,7 @@ func CreateTestRepo() (string, error) {
 	request.Header.Add("Authorization", bearer)
 	request.Header.Add("Content-Type", "application/json")
 
-	client := &http.Client{}
+	client := NewClient()
 	response, err := client.Do(request)
 
 	if err != nil {
@@ -419,7 +444,7 @@ func DeleteTestRepo() (string, error) {
 	request, err := http.NewRequest("DELETE", requestPath, nil)
 	request.Header.Add("Authorization", bearer)
 	
-	client := &http.Client{}
+	client := NewClient()
 	response, err := client.Do(request)
 
 	if err != nil {
@@ -465,3 +490,222 @@ func DeleteTestDirectory(dirPath string) {
 		LogTxtHandler().Info("Successfully removed test directory: " + dirPath)
 	}
 }
+
+func SendRequest(method, requestPath string, body io.Reader, contentType string) ([]byte, int, error) {
+	// Sends an authenticated request to Artifactory and returns the response body and status code
+	// Uses local request/response variables so it's safe to call from multiple goroutines
+	return sendRequest(method, requestPath, body, contentType, false)
+}
+
+func SendRequestNoCache(method, requestPath string, body io.Reader, contentType string) ([]byte, int, error) {
+	// Same as SendRequest, but always goes to Artifactory, even when the response cache is enabled
+	// Used for reads that must see the current state, like compare-and-set property checks and existence checks
+	return sendRequest(method, requestPath, body, contentType, true)
+}
+
+func sendRequest(method, requestPath string, body io.Reader, contentType string, noCache bool) ([]byte, int, error) {
+	bearer := SetBearer(util.Token)
+	LogTxtHandler().Debug("REQUEST: Sending '" + method + "' request to: " + requestPath)
+
+	request, err := http.NewRequest(method, requestPath, body)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		LogTxtHandler().Error("Unable to parse URL - " + strErr)
+		return nil, 0, err
+	}
+	request.Header.Add("Authorization", bearer)
+	if contentType != "" {
+		request.Header.Add("Content-Type", contentType)
+	}
+	if noCache {
+		request = request.WithContext(context.WithValue(request.Context(), noCacheKey{}, true))
+	}
+
+	client := NewClient()
+	response, err := client.Do(request)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		LogTxtHandler().Error("Error on API response from '" + method + "' " + requestPath + " - " + strErr)
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+
+	respBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		LogTxtHandler().Error("Error reading response body. " + strErr)
+		return nil, response.StatusCode, err
+	}
+	LogTxtHandler().Debug("REQUEST RESPONSE: " + string(respBody))
+	return respBody, response.StatusCode, nil
+}
+
+func ParseArtifUriForRepoPathName(serverApi, artifactUri string) (string, string, string) {
+	// Splits an artifact URI (http://server.com:8081/artifactory/api/storage/repo-key/folder/artifact.ext)
+	// into its repo ('repo-key'), path ('folder'), and name ('artifact.ext')
+	// Items at the root of a repo have a path of '.', matching how AQL reports them
+	if serverApi == "" {
+		serverApi = util.ServerApi
+	}
+	itemPath, found := strings.CutPrefix(artifactUri, TrimEndSlashUrl(serverApi) + "/storage/")
+	if !found {
+		// The URI may come from a different form of the server's address (ex: another port or scheme), so
+		// fall back to everything after the storage API
+		if _, after, hasStorage := strings.Cut(artifactUri, "/api/storage/"); hasStorage {
+			itemPath = after
+		}
+	}
+	itemPath = strings.Trim(itemPath, "/")
+
+	segments := strings.Split(itemPath, "/")
+	repo := segments[0]
+	if len(segments) == 1 {
+		return repo, ".", ""
+	}
+	name := segments[len(segments)-1]
+	folder := strings.Join(segments[1:len(segments)-1], "/")
+	if folder == "" {
+		folder = "."
+	}
+	return repo, folder, name
+}
+
+func SetArtifUriFromRepoPathName(serverApi, repo, itemPath, name string) string {
+	// Reverse of ParseArtifUriForRepoPathName; forms the artifact URI from a repo, path, and name
+	if serverApi == "" {
+		serverApi = util.ServerApi
+	}
+	artifUri := TrimEndSlashUrl(serverApi) + "/storage/" + repo
+	if itemPath != "" && itemPath != "." {
+		artifUri = artifUri + "/" + strings.Trim(itemPath, "/")
+	}
+	if name != "" {
+		artifUri = artifUri + "/" + name
+	}
+	return artifUri
+}
+
+func GetFileChecksums(filePath string) (string, string, string, error) {
+	// Reads the local file once and returns its SHA1, MD5, and SHA256 checksums as hex strings
+	file, err := os.Open(filePath)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		LogTxtHandler().Error("Error opening file: " + filePath + " - " + strErr)
+		return "", "", "", err
+	}
+	defer file.Close()
+
+	sha1Hash := sha1.New()
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(sha1Hash, md5Hash, sha256Hash), file)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		LogTxtHandler().Error("Error reading file: " + filePath + " - " + strErr)
+		return "", "", "", err
+	}
+
+	return hex.EncodeToString(sha1Hash.Sum(nil)), hex.EncodeToString(md5Hash.Sum(nil)), hex.EncodeToString(sha256Hash.Sum(nil)), nil
+}
+
+func GlobToRegexp(pattern string) *regexp.Regexp {
+	// Converts an Artifactory path glob (ex: 'images-local:windows/**/win2022-*/*.ovf') to an anchored regular expression
+	// '*' matches within a single folder/name, '?' matches one character, and '**' matches any number of folders (including none)
+	// A 'repo:path' pattern is treated the same as 'repo/path'
+	pattern = strings.TrimPrefix(pattern, "/")
+	if repo, rest, found := strings.Cut(pattern, ":"); found && !strings.Contains(repo, "/") {
+		pattern = repo + "/" + rest
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for idx := 0; idx < len(pattern); idx++ {
+		char := pattern[idx]
+		switch {
+		case strings.HasPrefix(pattern[idx:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			idx += 2
+		case strings.HasPrefix(pattern[idx:], "/**") && idx+3 == len(pattern):
+			expr.WriteString("(?:/.*)?")
+			idx += 2
+		case strings.HasPrefix(pattern[idx:], "**"):
+			expr.WriteString(".*")
+			idx++
+		case char == '*':
+			expr.WriteString("[^/]*")
+		case char == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[idx:idx+1]))  // byte by byte, so multi-byte UTF-8 characters stay intact
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+func MatchGlob(pattern, artifPath string) bool {
+	// Checks whether an artifact path ('repo/folder/artifact.ext' or '/repo/folder/artifact.ext') matches a path glob
+	// See GlobToRegexp for the supported syntax; matching is CASE SENSITIVE
+	return GlobToRegexp(pattern).MatchString(strings.TrimPrefix(artifPath, "/"))
+}
+
+// Artifactory timestamps are ISO-8601; older versions leave the colon out of the UTC offset (ex: +0100)
+var artifTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999-0700", "2006-01-02T15:04:05.999999999Z0700"}
+
+func ParseArtifTime(value string) (time.Time, error) {
+	// Parses an Artifactory timestamp (ex: '2024-10-01T14:03:21.123-04:00') into a time.Time
+	// An empty value returns the zero time without error
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range artifTimeLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+	err := errors.New("Unable to parse timestamp '" + value + "'; expected ISO-8601 format.")
+	LogTxtHandler().Debug("Unable to parse timestamp '" + value + "'; expected ISO-8601 format.")
+	return time.Time{}, err
+}
+
+var sizeUnits = []string{"bytes", "KB", "MB", "GB", "TB", "PB"}
+
+func FormatSize(size int64) string {
+	// Formats a size in bytes the way Artifactory displays it (ex: 1536 --> '1.50 KB'); units are powers of 1024
+	if size < 1024 {
+		return strconv.FormatInt(size, 10) + " bytes"
+	}
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < len(sizeUnits)-1 {
+		value = value / 1024
+		unit++
+	}
+	return strconv.FormatFloat(value, 'f', 2, 64) + " " + sizeUnits[unit]
+}
+
+func ParseSize(value string) (int64, error) {
+	// Reverse of FormatSize; parses a size as Artifactory displays it (ex: '3.48 GB', '0 bytes', '1,024 bytes') into bytes
+	// Anything after the unit, such as a percentage ('32.22 GB (15.77%)'), is ignored
+	fields := strings.Fields(strings.ReplaceAll(value, ",", ""))
+	if len(fields) == 0 {
+		return 0, errors.New("Unable to parse an empty size.")
+	}
+	number, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, errors.New("Unable to parse size '" + value + "'.")
+	}
+	multiplier := 1.0
+	if len(fields) > 1 {
+		unit := slices.IndexFunc(sizeUnits, func(u string) bool { return strings.EqualFold(u, fields[1]) })
+		if unit < 0 && strings.EqualFold(fields[1], "byte") {
+			unit = 0
+		}
+		if unit < 0 {
+			return 0, errors.New("Unable to parse size '" + value + "'; unknown unit '" + fields[1] + "'.")
+		}
+		multiplier = math.Pow(1024, float64(unit))
+	}
+	return int64(math.Round(number * multiplier)), nil
+}
@@ -0,0 +1,211 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// TransferOptions control a copy or move
+// DryRun checks the operation without changing anything; SuppressLayouts stops Artifactory from translating paths between
+// repos with different layouts; FailFast stops at the first error instead of continuing with the remaining files
+type TransferOptions struct {
+	DryRun			bool
+	SuppressLayouts	bool
+	FailFast		bool
+}
+
+// TransferItem is a file or folder copied or moved, as Artifactory paths ('/repo-key/folder/artifact.ext')
+type TransferItem struct {
+	From	string
+	To		string
+}
+
+// TransferResult is the outcome of a copy or move
+// Items are what Artifactory reported as transferred (files, or the folder as a whole); see Errors for what wasn't
+type TransferResult struct {
+	Source		string
+	Target		string
+	DryRun		bool
+	Items		[]TransferItem
+	Messages	[]string
+	Errors		[]string
+}
+
+// Matches the 'repo-key:path to repo-key:path' part of a copy or move message
+var transferMsgRegexp = regexp.MustCompile(`([^\s:]+):(\S*) to ([^\s:]+):(\S*)`)
+
+func transferMsgPath(repoKey, itemPath string) string {
+	// Turns a message's 'repo-key:path' into an Artifactory path ('/repo-key/path')
+	return strings.TrimSuffix("/" + repoKey + "/" + strings.Trim(itemPath, "/"), "/")
+}
+
+func boolParam(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+func transferItem(operation, sourcePath, targetPath string, opts TransferOptions) (TransferResult, error) {
+	// Copies or moves a file or folder within Artifactory; no content is downloaded or re-uploaded
+	sourcePath = "/" + strings.Trim(sourcePath, "/")
+	targetPath = "/" + strings.Trim(targetPath, "/")
+	result := TransferResult{Source: sourcePath, Target: targetPath, DryRun: opts.DryRun}
+
+	if sourcePath == "/" || targetPath == "/" || !strings.Contains(sourcePath[1:], "/") {
+		err := errors.New("Source and target paths are required, and the source can't be a whole repo.")
+		common.LogTxtHandler().Error("Source path: " + sourcePath + ", target path: " + targetPath)
+		common.LogTxtHandler().Error("Source and target paths are required, and the source can't be a whole repo.")
+		return result, err
+	}
+	if sourcePath == targetPath {
+		err := errors.New("Source and target paths can't be the same.")
+		common.LogTxtHandler().Error("Source and target paths can't be the same.")
+		return result, err
+	}
+
+	requestPath := util.ServerApi + "/" + operation + sourcePath + "?to=" + url.QueryEscape(targetPath) +
+		"&dry=" + boolParam(opts.DryRun) + "&suppressLayouts=" + boolParam(opts.SuppressLayouts) + "&failFast=" + boolParam(opts.FailFast)
+	body, status, err := common.SendRequest("POST", requestPath, nil, "")
+	if err != nil {
+		return result, err
+	}
+
+	// JSON return is a list of messages, each with a level (INFO, WARN, ERROR) and text
+	var jsonData struct {
+		Messages []struct {
+			Level	string	`json:"level"`
+			Message	string	`json:"message"`
+		}	`json:"messages"`
+	}
+	if len(body) != 0 {
+		err = json.Unmarshal(body, &jsonData)
+		if err != nil {
+			strErr := fmt.Sprintf("%v\n", err)
+			common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+			return result, err
+		}
+	}
+	// Items are taken from the messages that aren't errors, so only what was actually transferred is listed
+	// Ex: 'copying images-dev:win2022/win2022.ova to images-prod:win2022/win2022.ova completed successfully, ...'
+	for _, m := range jsonData.Messages {
+		if strings.EqualFold(m.Level, "ERROR") {
+			result.Errors = append(result.Errors, m.Message)
+			common.LogTxtHandler().Error(m.Message)
+			continue
+		}
+		result.Messages = append(result.Messages, m.Message)
+		common.LogTxtHandler().Debug(m.Level + ": " + m.Message)
+		if match := transferMsgRegexp.FindStringSubmatch(m.Message); match != nil {
+			result.Items = append(result.Items, TransferItem{From: transferMsgPath(match[1], match[2]), To: transferMsgPath(match[3], match[4])})
+		}
+	}
+	if len(result.Items) == 0 && status == http.StatusOK && len(result.Errors) == 0 {
+		result.Items = []TransferItem{{From: sourcePath, To: targetPath}}
+	}
+
+	if status != http.StatusOK || len(result.Errors) != 0 {
+		msg := "Unable to " + operation + " " + sourcePath + " to " + targetPath + " (status code " + fmt.Sprint(status) + ")"
+		if len(result.Errors) != 0 {
+			msg = msg + ": " + strings.Join(result.Errors, "; ")
+		}
+		err := errors.New(msg)
+		common.LogTxtHandler().Error("Unable to " + operation + " " + sourcePath + " to " + targetPath + " (status code " + fmt.Sprint(status) + ")")
+		return result, err
+	}
+	return result, nil
+}
+
+func CopyItem(sourcePath, targetPath string, opts TransferOptions) (TransferResult, error) {
+	// Copies a file or folder ('/repo-key/folder/...') to the target path, which can be in another repo
+	// The target path is the full new path of the item (ex: '/images-dev/win2022' to '/images-prod/win2022')
+	common.LogTxtHandler().Info(">>> Copying " + sourcePath + " to " + targetPath + "...")
+	return transferItem("copy", sourcePath, targetPath, opts)
+}
+
+func MoveItem(sourcePath, targetPath string, opts TransferOptions) (TransferResult, error) {
+	// Moves a file or folder ('/repo-key/folder/...') to the target path, which can be in another repo
+	// The target path is the full new path of the item (ex: '/images-dev/win2022' to '/images-prod/win2022')
+	common.LogTxtHandler().Info(">>> Moving " + sourcePath + " to " + targetPath + "...")
+	return transferItem("move", sourcePath, targetPath, opts)
+}
+
+func RenameItem(itemPath, newName string, opts TransferOptions) (TransferResult, error) {
+	// Renames a file or folder in place; newName is just the new name, not a path
+	common.LogTxtHandler().Info(">>> Renaming " + itemPath + " to " + newName + "...")
+
+	if newName == "" || strings.ContainsAny(newName, "/\\") {
+		err := errors.New("New name can't be empty or contain slashes.")
+		common.LogTxtHandler().Error("New name can't be empty or contain slashes.")
+		return TransferResult{Source: itemPath}, err
+	}
+	itemPath = "/" + strings.Trim(itemPath, "/")
+	targetPath := itemPath[:strings.LastIndex(itemPath, "/")] + "/" + newName
+	return transferItem("move", itemPath, targetPath, opts)
+}
+
+func CreateFolder(folderPath string) (string, error) {
+	// Creates a folder ('/repo-key/folder/subfolder'), including any missing parent folders; returns "201" if successful
+	// Creating a folder that already exists isn't an error
+	common.LogTxtHandler().Info(">>> Creating folder: " + folderPath + "...")
+
+	folderPath = strings.Trim(folderPath, "/")
+	if !strings.Contains(folderPath, "/") {
+		err := errors.New("Folder path must include the repo key and at least one folder.")
+		common.LogTxtHandler().Error("Folder path must include the repo key and at least one folder.")
+		return "", err
+	}
+
+	trimmedBase, err := common.TrimApiFromServer("")
+	if err != nil {
+		return "", err
+	}
+	body, status, err := common.SendRequest("PUT", trimmedBase + "/" + folderPath + "/", nil, "")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusCreated && status != http.StatusOK {
+		err := errors.New("Unable to create folder: /" + folderPath + " (status code " + fmt.Sprint(status) + "): " + string(body))
+		common.LogTxtHandler().Error("Unable to create folder: /" + folderPath + " (status code " + fmt.Sprint(status) + ")")
+		return fmt.Sprint(status), err
+	}
+	common.LogTxtHandler().Info("Folder: /" + folderPath + " created.")
+	return "201", nil
+}
+
+func DeleteItem(itemPath string) (string, error) {
+	// Deletes a file or a whole folder and everything under it by its path ('/repo-key/folder/file.ext'); returns "204" if successful
+	// Unlike DeleteArtifact, this takes the Artifactory path rather than the artifact URI
+	common.LogTxtHandler().Info(">>> Deleting item: " + itemPath + "...")
+
+	itemPath = strings.Trim(itemPath, "/")
+	if !strings.Contains(itemPath, "/") {
+		err := errors.New("Item path must include the repo key and at least one folder or file; deleting a whole repo isn't supported.")
+		common.LogTxtHandler().Error("Item path must include the repo key and at least one folder or file; deleting a whole repo isn't supported.")
+		return "", err
+	}
+
+	trimmedBase, err := common.TrimApiFromServer("")
+	if err != nil {
+		return "", err
+	}
+	body, status, err := common.SendRequest("DELETE", trimmedBase + "/" + itemPath, nil, "")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusNoContent {
+		err := errors.New("Unable to delete item: /" + itemPath + " (status code " + fmt.Sprint(status) + "): " + string(body))
+		common.LogTxtHandler().Error("Unable to delete item: /" + itemPath + " (status code " + fmt.Sprint(status) + ")")
+		return fmt.Sprint(status), err
+	}
+	common.LogTxtHandler().Info("Item: /" + itemPath + " deleted.")
+	return "204", nil
+}
@@ -10,9 +10,9 @@ import (
 	"os"
 	"path"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/raynaluzier/artifactory-go-sdk/common"
 	"github.com/raynaluzier/artifactory-go-sdk/util"
@@ -45,155 +45,179 @@ type artifJson struct {
 		Sha256		string	`json:"sha256"`				
 	}   `json:"originalChecksums"`
 	Uri 			string	`json:"uri"`
+	Children	[]struct {
+		Uri			string	`json:"uri"`
+		Folder		bool	`json:"folder"`
+	}	`json:"children"`
 }
 
+// ItemChecksums are the checksums of a file; OriginalChecksums on ItemInfo are those sent by the client when it was deployed
+type ItemChecksums struct {
+	Sha1			string
+	Md5				string
+	Sha256			string
+}
+
+// ItemInfo is the storage info of a file or folder
+// DownloadUri, MimeType, Size, and the checksums are only set for files; Children (direct children only) only for folders
+type ItemInfo struct {
+	Uri					string
+	Repo				string
+	Path				string
+	Folder				bool
+	Created				time.Time
+	CreatedBy			string
+	LastModified		time.Time
+	ModifiedBy			string
+	LastUpdated			time.Time
+	DownloadUri			string
+	MimeType			string
+	Size				int64
+	Checksums			ItemChecksums
+	OriginalChecksums	ItemChecksums
+	Children			[]Contents
+}
+
+// Layout of Artifactory timestamps (ex: '2024-10-01T14:03:21.123-04:00'), used where dates are still returned as strings
+const artifTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
 var request *http.Request
 var err error
-var foundPaths []string
 
+// Wrapped by the error GetItemInfo returns when the file or folder doesn't exist; check with errors.Is
+var ErrItemNotFound = errors.New("Item not found")
 
-func ListRepos() ([]string, error) {
-	var listRepos []string
-	bearer := common.SetBearer(util.Token)
-	requestPath := util.ServerApi + "/repositories"
-	
-	common.LogTxtHandler().Info(">>> Getting list of available repos...")
-	common.LogTxtHandler().Debug("REQUEST: Sending 'GET' request to: " + requestPath)
 
-	request, err = http.NewRequest("GET", requestPath, nil)
-	request.Header.Add("Authorization", bearer)
-	
-	client := &http.Client{}
-	response, err := client.Do(request)
+func GetItemInfo(artifUri string) (ItemInfo, error) {
+	// Gets the storage info of a file or folder by its artifact URI or Artifactory path ('/repo-key/folder/artifact.ext')
+	artifUri = toArtifUri(artifUri)
+	info := ItemInfo{Uri: artifUri}
+	common.LogTxtHandler().Debug(">>> Getting item info for: " + artifUri + "...")
+
+	body, status, err := common.SendRequest("GET", artifUri, nil, "")
+	if err != nil {
+		return info, err
+	}
+	if status == http.StatusNotFound {
+		// Logged at debug level only; callers decide whether a missing item is an error (ex: before an upload it isn't)
+		err := fmt.Errorf("%w: %s", ErrItemNotFound, artifUri)
+		common.LogTxtHandler().Debug("Item not found: " + artifUri)
+		return info, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Unable to get item info for: " + artifUri + " (status code " + strconv.Itoa(status) + ")")
+		common.LogTxtHandler().Error("Unable to get item info for: " + artifUri + " (status code " + strconv.Itoa(status) + ")")
+		return info, err
+	}
+
+	var jsonData artifJson
+	err = json.Unmarshal(body, &jsonData)
 	if err != nil {
 		strErr := fmt.Sprintf("%v\n", err)
-		common.LogTxtHandler().Error("Error on API response from 'GET' " + requestPath + " - " + strErr)
-	} else {
-		defer response.Body.Close()
-		body, err := io.ReadAll(response.Body)
-		common.LogTxtHandler().Debug("REQUEST RESPONSE: " + string(body))
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return info, err
+	}
 
-		// JSON return is an array of strings '[{"key":"repo_name1, "type":"LOCAL"...}, {"key":"repo_name2"}...]'
-		type reposJson struct {
-			Key 		string	`json:"key"`
-			Description	string	`json:"description"`
-			Type		string	`json:"type"`
-			Url 		string	`json:"url"`
-			PackageType	string	`json:"packageType"`
-		}
+	info.Repo = jsonData.Repo
+	info.Path = jsonData.Path
+	info.Folder = jsonData.DownloadUri == ""          // Only files have a download URI
+	info.CreatedBy = jsonData.CreatedBy
+	info.ModifiedBy = jsonData.ModifiedBy
+	info.DownloadUri = jsonData.DownloadUri
+	info.MimeType = jsonData.MimeType
+	info.Checksums = ItemChecksums(jsonData.Checksums)
+	info.OriginalChecksums = ItemChecksums(jsonData.OriginalChecksums)
+	for _, c := range jsonData.Children {
+		info.Children = append(info.Children, Contents{Child: c.Uri, IsFolder: c.Folder})
+	}
 
-		var jsonData []reposJson
-		err = json.Unmarshal(body, &jsonData)
+	if jsonData.Size != "" {
+		info.Size, err = strconv.ParseInt(jsonData.Size, 10, 64)
 		if err != nil {
-			strErr := fmt.Sprintf("%v\n", err)
-			common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+			common.LogTxtHandler().Error("Unable to parse size '" + jsonData.Size + "' of: " + artifUri)
+			return info, err
 		}
-
-		if len(jsonData) != 0 {
-			for _, k := range jsonData {
-				listRepos = append(listRepos, k.Key)
-				common.LogTxtHandler().Debug("FOUND REPO: " + k.Key)
-			}
-			return listRepos, nil
-		} else {
-			err := errors.New("No repos found")
-			common.LogTxtHandler().Warn("No repos found")
-			return nil, err
+	}
+	for _, t := range []struct {
+		value	string
+		field	*time.Time
+	}{{jsonData.Created, &info.Created}, {jsonData.LastModified, &info.LastModified}, {jsonData.LastUpdated, &info.LastUpdated}} {
+		*t.field, err = common.ParseArtifTime(t.value)
+		if err != nil {
+			common.LogTxtHandler().Error("Unable to parse timestamp '" + t.value + "' of: " + artifUri)
+			return info, err
 		}
 	}
-	return listRepos, nil
+	return info, nil
 }
 
-func GetDownloadUri(artifUri string) (string, error) {
-	bearer := common.SetBearer(util.Token)
-	var downloadUri string
-	common.LogTxtHandler().Info(">>> Getting Download URI from Artifact URI: " + artifUri + "...")
-
-	if (artifUri != "") {
-		common.LogTxtHandler().Debug("REQUEST: Sending 'GET' request to: " + artifUri)
-		request, err = http.NewRequest("GET", artifUri, nil)
-		request.Header.Add("Authorization", bearer)
-
-		client := &http.Client{}
-		response, err := client.Do(request)
-
-		if err != nil {
-			strErr := fmt.Sprintf("%v\n", err)
-			common.LogTxtHandler().Error("Error on response. " + strErr)
-			return "", err
-		} else {
-			defer response.Body.Close()
-			body, err := io.ReadAll(response.Body)
-			common.LogTxtHandler().Debug("REQUEST RESPONSE: " + string(body))
+func ListRepos(filter ...RepoFilter) ([]string, error) {
+	// Optionally takes a filter to only list repos of a certain type (ex: 'local') and/or package type (ex: 'generic')
+	var listRepos []string
 
-			var jsonData *artifJson
-			err = json.Unmarshal(body, &jsonData)
-			if err != nil {
-				strErr := fmt.Sprintf("%v\n", err)
-				common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
-			}
+	repos, err := ListRepoSummaries(filter...)
+	if err != nil {
+		return nil, err
+	}
 
-			if jsonData.DownloadUri != "" {
-				downloadUri = jsonData.DownloadUri
-				common.LogTxtHandler().Info("DOWNLOAD URI RETRIEVED: " + downloadUri)
-				return downloadUri, nil
-			} else {
-				err = errors.New("There is no download URI for the artifact.")
-				common.LogTxtHandler().Warn("There is no download URI for the artifact.")
-				return "", err
-			}
+	if len(repos) != 0 {
+		for _, r := range repos {
+			listRepos = append(listRepos, r.Key)
 		}
+		return listRepos, nil
 	} else {
+		err := errors.New("No repos found")
+		common.LogTxtHandler().Warn("No repos found")
+		return nil, err
+	}
+}
+
+func GetDownloadUri(artifUri string) (string, error) {
+	// Returns the download URI of a file; see GetItemInfo for the rest of its storage info
+	common.LogTxtHandler().Info(">>> Getting Download URI from Artifact URI: " + artifUri + "...")
+
+	if artifUri == "" {
 		err := errors.New("No artifact URI was provided.")
 		common.LogTxtHandler().Error("Unable to get artifact's download URI without the artifact's URI.")
 		return "", err
 	}
+
+	info, err := GetItemInfo(artifUri)
+	if err != nil {
+		return "", err
+	}
+	if info.DownloadUri == "" {
+		err = errors.New("There is no download URI for the artifact.")
+		common.LogTxtHandler().Warn("There is no download URI for the artifact.")
+		return "", err
+	}
+	common.LogTxtHandler().Info("DOWNLOAD URI RETRIEVED: " + info.DownloadUri)
+	return info.DownloadUri, nil
 }
 
 func GetCreateDate(artifUri string) (string, error) {
-	bearer := common.SetBearer(util.Token)
-	var createdDate string
+	// Returns the created date of an artifact in Artifactory's format (ex: '2024-10-01T14:03:21.123-04:00')
+	// See GetItemInfo for the date as a time.Time, along with the rest of its storage info
 	common.LogTxtHandler().Info(">>> Getting Create Date for Artifact: " + artifUri + "...")
 
-	if (artifUri != "") {
-		common.LogTxtHandler().Debug("REQUEST: Sending 'GET' request to: " + artifUri)
-		request, err = http.NewRequest("GET", artifUri, nil)
-		request.Header.Add("Authorization", bearer)
-
-		client := &http.Client{}
-		response, err := client.Do(request)
-
-		if err != nil {
-			strErr := fmt.Sprintf("%v\n", err)
-			common.LogTxtHandler().Error("Error on response. " + strErr)
-			return "", err
-		} else {
-			defer response.Body.Close()
-			body, err := io.ReadAll(response.Body)
-			common.LogTxtHandler().Debug("REQUEST RESPONSE: " + string(body))
-
-			var jsonData *artifJson
-			err = json.Unmarshal(body, &jsonData)
-			if err != nil {
-				strErr := fmt.Sprintf("%v\n", err)
-				common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
-			}
-
-			if jsonData.Created != "" {
-				createdDate = jsonData.Created
-				common.LogTxtHandler().Debug("CREATE DATE RETRIEVED: " + createdDate)
-				return createdDate, nil
-			} else {
-				err = errors.New("There is no create date for the artifact.")
-				common.LogTxtHandler().Warn("There is no create date for the artifact.")
-				return "", err
-			}
-		}
-	} else {
+	if artifUri == "" {
 		err := errors.New("No artifact URI was provided.")
 		common.LogTxtHandler().Error("Unable to get artifact's created date without the artifact's URI.")
 		return "", err
 	}
+
+	info, err := GetItemInfo(artifUri)
+	if err != nil {
+		return "", err
+	}
+	if info.Created.IsZero() {
+		err = errors.New("There is no create date for the artifact.")
+		common.LogTxtHandler().Warn("There is no create date for the artifact.")
+		return "", err
+	}
+	createdDate := info.Created.Format(artifTimeFormat)
+	common.LogTxtHandler().Debug("CREATE DATE RETRIEVED: " + createdDate)
+	return createdDate, nil
 }
 
 func GetArtifactNameFromUri(artifUri string) (string) {
@@ -247,7 +271,7 @@ func RetrieveArtifact(downloadUri string) (string, error) {
 		request, err = http.NewRequest("GET", downloadUri, nil)
 		request.Header.Add("Authorization", bearer)
 
-		client := &http.Client{}
+		client := common.NewClient()
 		response, err := client.Do(request)
 
 		if err != nil {
@@ -312,7 +336,10 @@ func UploadFile(sourcePath, targetPath string) (string, error) {
 	var fileName string
 	var found bool
 	bearer := common.SetBearer(util.Token)
-	trimmedBase := util.ServerApi[:len(util.ServerApi)-4]               // Removing '/api' from base URI
+	trimmedBase, err := common.TrimApiFromServer("")
+	if err != nil {
+		return "", err
+	}
 
 	common.LogTxtHandler().Info(">>> Checking for file: " + sourcePath + "...")
 
@@ -376,14 +403,25 @@ func UploadFile(sourcePath, targetPath string) (string, error) {
 			}
 			
 			newArtifactPath := trimmedBase + targetPath + fileName                  // Forms: http://artifactory_base_api_url/repo-key/folder/artifact.txt
-			data := strings.NewReader("@/" + sourcePath)                            // Formats the payload appropriately
-			fmt.Println(data)
+			data, err := os.Open(filePath + fileName)                              // The file's contents are the payload
+			if err != nil {
+				strErr := fmt.Sprintf("%v\n", err)
+				common.LogTxtHandler().Error("Unable to open file: " + filePath + fileName + " - " + strErr)
+				return "", err
+			}
+			defer data.Close()
 			common.LogTxtHandler().Debug("REQUEST: Sending 'PUT' request to: " + newArtifactPath)
 			
 			request, err = http.NewRequest("PUT", newArtifactPath, data)
+			if err != nil {
+				return "", err
+			}
+			if stat, err := data.Stat(); err == nil {
+				request.ContentLength = stat.Size()                                 // Otherwise the file is sent chunked
+			}
 			request.Header.Add("Authorization", bearer)
 	
-			client := &http.Client{}
+			client := common.NewClient()
 			response, err := client.Do(request)
 
 			if err != nil {
@@ -425,6 +463,86 @@ func UploadFile(sourcePath, targetPath string) (string, error) {
 	}
 }
 
+func UploadFileIfChanged(sourcePath, targetPath string) (string, bool, error) {
+	// Uploads a file like 'UploadFile', but skips the transfer when the content is already in Artifactory
+	// If the same file (by SHA256) already exists at the target, nothing is sent; otherwise a checksum deploy is tried,
+	// which links content already stored elsewhere in Artifactory without re-sending it
+	// Falls back to 'UploadFile' if the content isn't stored anywhere yet
+	// Returns the download URI and whether the transfer was skipped
+	var fileName string
+	trimmedBase, err := common.TrimApiFromServer("")
+	if err != nil {
+		return "", false, err
+	}
+
+	common.LogTxtHandler().Info(">>> Checking for identical content before uploading: " + sourcePath + "...")
+
+	if len(sourcePath) == 0 || targetPath == "" {
+		err := errors.New("Cannot upload file without source path/file, target path, and artifact file name")
+		common.LogTxtHandler().Error("Cannot upload file without source path/file, target path, and artifact file name")
+		return "", false, err
+	}
+
+	if common.CheckPathType(sourcePath) {
+		segments := strings.Split(sourcePath, "\\")
+		fileName = segments[len(segments)-1]
+	} else {
+		segments := strings.Split(sourcePath, "/")
+		fileName = segments[len(segments)-1]
+	}
+
+	sha1, md5, sha256, err := common.GetFileChecksums(sourcePath)
+	if err != nil {
+		// Leave missing files and the like to 'UploadFile', which handles them the same as before
+		downloadUri, err := UploadFile(sourcePath, targetPath)
+		return downloadUri, false, err
+	}
+
+	targetPath = common.CheckAddSlashToPath(common.EscapeSpecialChars(targetPath))
+	artifUri := util.ServerApi + "/storage" + targetPath + fileName
+
+	// Same content already at the target?
+	info, err := GetItemInfo(artifUri)
+	if err == nil && !info.Folder && info.Checksums.Sha256 == sha256 {
+		common.LogTxtHandler().Info("Identical file already exists at: " + targetPath + fileName + "; skipping upload.")
+		return info.DownloadUri, true, nil
+	}
+
+	// Same content stored elsewhere? A checksum deploy links it without sending the file
+	request, err := http.NewRequest("PUT", trimmedBase + targetPath + fileName, nil)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error creating request. " + strErr)
+		return "", false, err
+	}
+	request.Header.Add("Authorization", common.SetBearer(util.Token))
+	request.Header.Add("X-Checksum-Deploy", "true")
+	request.Header.Add("X-Checksum-Sha1", sha1)
+	request.Header.Add("X-Checksum-Sha256", sha256)
+	request.Header.Add("X-Checksum", md5)
+	common.LogTxtHandler().Debug("REQUEST: Sending checksum deploy 'PUT' request to: " + trimmedBase + targetPath + fileName)
+
+	client := common.NewClient()
+	response, err := client.Do(request)
+	if err == nil {
+		defer response.Body.Close()
+		body, _ := io.ReadAll(response.Body)
+		common.LogTxtHandler().Debug("REQUEST RESPONSE: " + string(body))
+
+		if response.StatusCode == http.StatusCreated {
+			var jsonData *artifJson
+			if json.Unmarshal(body, &jsonData) == nil && jsonData != nil && jsonData.DownloadUri != "" {
+				common.LogTxtHandler().Info("Content already stored in Artifactory; deployed by checksum to: " + targetPath + fileName)
+				return jsonData.DownloadUri, true, nil
+			}
+		}
+	}
+
+	common.LogTxtHandler().Debug("Content not found in Artifactory; uploading file.")
+	downloadUri, err := UploadFile(sourcePath, targetPath)
+	return downloadUri, false, err
+}
+
 func DeleteArtifact(artifUri string) (string, error) {
 	bearer := common.SetBearer(util.Token)
 	common.LogTxtHandler().Info(">>> Deleting Artifact: " + artifUri + "...")
@@ -434,7 +552,7 @@ func DeleteArtifact(artifUri string) (string, error) {
 		request, err = http.NewRequest("DELETE", artifUri, nil)
 		request.Header.Add("Authorization", bearer)
 
-		client := &http.Client{}
+		client := common.NewClient()
 		response, err := client.Do(request)
 
 		if err != nil {
@@ -468,29 +586,8 @@ func DeleteArtifact(artifUri string) (string, error) {
 }
 
 func GetLatestArtifactFromList(list []string) (string, error) {
-	var latestItem string
-	var dateMap []map[string]string
-
-	for item := 0; item < len(list); item++ {
-		addMap := make(map[string]string)
-		created, err := GetCreateDate(list[item])
-		if err != nil {
-			common.LogTxtHandler().Error("Error getting created date.")
-		}
-		common.LogTxtHandler().Info("CREATED DATE RETRIEVED:" + created)
-		addMap["artifact"] = list[item]
-		addMap["created"] = created
-		dateMap = append(dateMap, addMap)
-	}
-
-	sort.Slice(dateMap, func(i, j int) bool {
-		return dateMap[i]["created"] < dateMap[j]["created"]
-	})
-
-	latest := len(dateMap) - 1
-	latestItem = dateMap[latest]["artifact"]
-	common.LogTxtHandler().Info("LATEST ITEM: " + latestItem)
-	return latestItem, nil
+	// Returns the most recently created artifact in the list
+	return GetLatestArtifactFromListBy(list, ByCreated)
 }
 
 func GetArtifact(downloadUri string) (string, error) {
@@ -503,7 +600,7 @@ func GetArtifact(downloadUri string) (string, error) {
 		common.LogTxtHandler().Debug("REQUEST: Sending 'GET' request to: " + downloadUri)
 		request.Header.Add("Authorization", bearer)
 
-		client := &http.Client{}
+		client := common.NewClient()
 		response, err := client.Do(request)
 		if err != nil {
 			strErr := fmt.Sprintf("%v\n", err)
@@ -604,7 +701,11 @@ func CheckFileAndUpload(sourceDir, targetDir, fileName, imageName string) (strin
 	common.LogTxtHandler().Debug("Target Path: " + targetPath)
 
 	// 'UploadFile' validates file before upload
-	downloadUri, err = UploadFile(sourcePath, targetPath)
+	if util.SkipIdentical {
+		downloadUri, _, err = UploadFileIfChanged(sourcePath, targetPath)
+	} else {
+		downloadUri, err = UploadFile(sourcePath, targetPath)
+	}
 
 	if err != nil {
 		strErr := fmt.Sprintf("%v\n", err)
@@ -0,0 +1,217 @@
+package operations
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+)
+
+// ArtifactOrder compares two artifacts for latest-selection; it returns a negative number if a is older than b,
+// zero if they're the same, and a positive number if a is newer
+// The built-in orderings are ByCreated, ByLastModified, ByVersionProp, and ByNameVersion; any function with this signature can be used
+type ArtifactOrder func(a, b ArtifactDetails) (int, error)
+
+// Finds dotted version numbers in a name (ex: '2024.10.01' in 'win2022-2024.10.01.ova')
+var nameVersionRegexp = regexp.MustCompile(`\d+(?:\.\d+)*`)
+
+func compareTimes(a, b ArtifactDetails, field string, get func(ArtifactDetails) time.Time) (int, error) {
+	for _, d := range []ArtifactDetails{a, b} {
+		if !d.Found {
+			err := errors.New("Artifact not found: " + d.Uri)
+			common.LogTxtHandler().Error("Artifact not found: " + d.Uri)
+			return 0, err
+		}
+		if get(d).IsZero() {
+			err := errors.New("No " + field + " date for artifact: " + d.Uri)
+			common.LogTxtHandler().Error("No " + field + " date for artifact: " + d.Uri)
+			return 0, err
+		}
+	}
+	return get(a).Compare(get(b)), nil
+}
+
+func ByCreated(a, b ArtifactDetails) (int, error) {
+	// Orders artifacts by created date; timestamps are compared as times, so differing timezone offsets are handled
+	return compareTimes(a, b, "created", func(d ArtifactDetails) time.Time { return d.Created })
+}
+
+func ByLastModified(a, b ArtifactDetails) (int, error) {
+	// Orders artifacts by last modified date
+	return compareTimes(a, b, "last modified", func(d ArtifactDetails) time.Time { return d.LastModified })
+}
+
+func ByVersionProp(propKey string) ArtifactOrder {
+	// Orders artifacts by the version in the given property (ex: 'version=1.10.2'), compared semver-style
+	// An artifact without the property (or with more than one value for it) is an error
+	return func(a, b ArtifactDetails) (int, error) {
+		valA, err := getVersionProp(a, propKey)
+		if err != nil {
+			return 0, err
+		}
+		valB, err := getVersionProp(b, propKey)
+		if err != nil {
+			return 0, err
+		}
+		return CompareVersions(valA, valB), nil
+	}
+}
+
+func getVersionProp(d ArtifactDetails, propKey string) (string, error) {
+	values := d.Properties[propKey]
+	if len(values) != 1 || values[0] == "" {
+		err := errors.New("Artifact: " + d.Uri + " must have exactly one value for property '" + propKey + "' to be ordered by version.")
+		common.LogTxtHandler().Error("Artifact: " + d.Uri + " must have exactly one value for property '" + propKey + "' to be ordered by version.")
+		return "", err
+	}
+	return values[0], nil
+}
+
+func ByNameVersion(a, b ArtifactDetails) (int, error) {
+	// Orders artifacts by the version embedded in their name (ex: 'win2022-2024.10.01.ova' --> '2024.10.01')
+	valA, err := GetVersionFromName(path.Base(a.Uri))
+	if err != nil {
+		return 0, err
+	}
+	valB, err := GetVersionFromName(path.Base(b.Uri))
+	if err != nil {
+		return 0, err
+	}
+	return CompareVersions(valA, valB), nil
+}
+
+func GetVersionFromName(artifName string) (string, error) {
+	// Returns the version embedded in an artifact name; this is the last number (with dots) in the name,
+	// after removing the file extension (ex: 'win2022-2024.10.01.ova' --> '2024.10.01')
+	name := artifName
+	if ext := path.Ext(name); ext != "" && !nameVersionRegexp.MatchString(ext) {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	matches := nameVersionRegexp.FindAllString(name, -1)
+	if len(matches) == 0 {
+		err := errors.New("No version found in artifact name: " + artifName)
+		common.LogTxtHandler().Error("No version found in artifact name: " + artifName)
+		return "", err
+	}
+	return matches[len(matches)-1], nil
+}
+
+func CompareVersions(a, b string) int {
+	// Compares two versions semver-style: a leading 'v' is ignored, numeric parts are compared as numbers,
+	// a missing part counts as 0 (so '1.2' == '1.2.0'), and a pre-release ('1.2.0-rc1') is older than its release
+	// Build metadata ('+build5') is ignored; returns -1, 0, or 1
+	coreA, preA := splitVersion(a)
+	coreB, preB := splitVersion(b)
+
+	if c := compareVersionParts(strings.Split(coreA, "."), strings.Split(coreB, "."), true); c != 0 {
+		return c
+	}
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+	return compareVersionParts(strings.Split(preA, "."), strings.Split(preB, "."), false)
+}
+
+func splitVersion(version string) (string, string) {
+	version = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(version), "v"), "V")
+	version, _, _ = strings.Cut(version, "+")
+	core, pre, _ := strings.Cut(version, "-")
+	return core, pre
+}
+
+func compareVersionParts(partsA, partsB []string, padZero bool) int {
+	for idx := 0; idx < max(len(partsA), len(partsB)); idx++ {
+		if idx >= len(partsA) || idx >= len(partsB) {
+			if !padZero {
+				// A longer pre-release is newer when all preceding parts are equal
+				if idx >= len(partsA) {
+					return -1
+				}
+				return 1
+			}
+		}
+		partA, partB := "0", "0"
+		if idx < len(partsA) {
+			partA = partsA[idx]
+		}
+		if idx < len(partsB) {
+			partB = partsB[idx]
+		}
+
+		numA, errA := strconv.ParseUint(partA, 10, 64)
+		numB, errB := strconv.ParseUint(partB, 10, 64)
+		switch {
+		case errA == nil && errB == nil:
+			if numA != numB {
+				if numA < numB {
+					return -1
+				}
+				return 1
+			}
+		case errA == nil:
+			return -1        // Numeric parts are older than text parts
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(partA, partB); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func GetLatestFromDetailsBy(details []ArtifactDetails, order ArtifactOrder) (string, error) {
+	// Returns the URI of the latest artifact in the list by the given ordering
+	// If any comparison fails (ex: a missing or unparsable date), the error is returned rather than guessing
+	if len(details) == 0 {
+		err := errors.New("List of artifacts cannot be empty.")
+		common.LogTxtHandler().Error("List of artifacts cannot be empty.")
+		return "", err
+	}
+	if order == nil {
+		order = ByCreated
+	}
+
+	latest := details[0]
+	if len(details) == 1 {
+		// Still check the single artifact can be ordered (ex: it exists and has a date)
+		_, err := order(latest, latest)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, d := range details[1:] {
+		c, err := order(d, latest)
+		if err != nil {
+			return "", err
+		}
+		if c >= 0 {
+			latest = d        // On a tie, the later artifact in the list wins
+		}
+	}
+
+	common.LogTxtHandler().Info("LATEST ITEM: " + latest.Uri)
+	return latest.Uri, nil
+}
+
+func GetLatestArtifactFromListBy(list []string, order ArtifactOrder) (string, error) {
+	// Same as GetLatestArtifactFromList, but with the given ordering
+	// Dates and properties for the whole list are retrieved in bulk rather than one request per artifact
+	details, err := GetArtifactDetailsForList(list)
+	if err != nil {
+		common.LogTxtHandler().Error("Error getting artifact details.")
+		return "", err
+	}
+	return GetLatestFromDetailsBy(details, order)
+}
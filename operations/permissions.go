@@ -0,0 +1,190 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// PermissionTarget grants users and groups actions on a set of repos
+// Ex actions: 'read', 'annotate', 'write', 'delete', 'manage'
+type PermissionTarget struct {
+	Name	string			`json:"name"`
+	Repo	*PermissionRepo	`json:"repo,omitempty"`
+}
+
+type PermissionRepo struct {
+	Repositories	[]string			`json:"repositories"`
+	IncludePatterns	[]string			`json:"include-patterns,omitempty"`
+	ExcludePatterns	[]string			`json:"exclude-patterns,omitempty"`
+	Actions			PermissionActions	`json:"actions"`
+}
+
+type PermissionActions struct {
+	Users	map[string][]string	`json:"users,omitempty"`
+	Groups	map[string][]string	`json:"groups,omitempty"`
+}
+
+func permissionPath(name string) string {
+	return util.ServerApi + "/v2/security/permissions/" + url.PathEscape(name)
+}
+
+func GetPermissionTarget(name string) (PermissionTarget, bool, error) {
+	// Gets a permission target by name; the bool is false (with no error) if it doesn't exist
+	common.LogTxtHandler().Info(">>> Getting permission target: " + name + "...")
+
+	sections, exists, err := getPermissionSections(name)
+	if err != nil || !exists {
+		return PermissionTarget{}, exists, err
+	}
+	data, _ := json.Marshal(sections)
+
+	var target PermissionTarget
+	err = json.Unmarshal(data, &target)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return PermissionTarget{}, false, err
+	}
+	return target, true, nil
+}
+
+func getPermissionSections(name string) (map[string]json.RawMessage, bool, error) {
+	// Gets a permission target as its raw sections ('name', 'repo', 'build', 'releaseBundle', ...), including those
+	// PermissionTarget doesn't model; the bool is false (with no error) if it doesn't exist
+	if name == "" {
+		err := errors.New("Permission target name can't be empty.")
+		common.LogTxtHandler().Error("Permission target name can't be empty.")
+		return nil, false, err
+	}
+
+	body, status, err := common.SendRequest("GET", permissionPath(name), nil, "")
+	if err != nil {
+		return nil, false, err
+	}
+	if status == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if status != http.StatusOK {
+		err := errors.New("Unable to get permission target '" + name + "' (status code " + fmt.Sprint(status) + ").")
+		common.LogTxtHandler().Error("Unable to get permission target '" + name + "' (status code " + fmt.Sprint(status) + ").")
+		return nil, false, err
+	}
+
+	var sections map[string]json.RawMessage
+	err = json.Unmarshal(body, &sections)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, false, err
+	}
+	return sections, true, nil
+}
+
+func SavePermissionTarget(target PermissionTarget) (string, error) {
+	// Creates the permission target, or updates it if it already exists; returns "200" if successful
+	// An update replaces the whole target, so the existing target's other sections (ex: 'build', 'releaseBundle') are
+	// sent back unchanged; its 'repo' section is only replaced if target.Repo is set
+	common.LogTxtHandler().Info(">>> Saving permission target: " + target.Name + "...")
+
+	if target.Name == "" {
+		err := errors.New("Permission target name can't be empty.")
+		common.LogTxtHandler().Error("Permission target name can't be empty.")
+		return "", err
+	}
+	sections, exists, err := getPermissionSections(target.Name)
+	if err != nil {
+		return "", err
+	}
+	method := "POST"
+	if exists {
+		method = "PUT"
+	} else {
+		sections = make(map[string]json.RawMessage)
+	}
+
+	data, err := json.Marshal(target)
+	if err == nil {
+		var targetSections map[string]json.RawMessage
+		err = json.Unmarshal(data, &targetSections)
+		for key, section := range targetSections {
+			sections[key] = section
+		}
+	}
+	if err == nil {
+		data, err = json.Marshal(sections)
+	}
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Unable to form permission target - " + strErr)
+		return "", err
+	}
+	body, status, err := common.SendRequest(method, permissionPath(target.Name), strings.NewReader(string(data)), "application/json")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK && status != http.StatusCreated {
+		err := errors.New("Unable to save permission target '" + target.Name + "' (status code " + fmt.Sprint(status) + "): " + string(body))
+		common.LogTxtHandler().Error("Unable to save permission target '" + target.Name + "' (status code " + fmt.Sprint(status) + ")")
+		return fmt.Sprint(status), err
+	}
+	return "200", nil
+}
+
+func DeletePermissionTarget(name string) (string, error) {
+	// Deletes a permission target; returns "200" if successful
+	common.LogTxtHandler().Info(">>> Deleting permission target: " + name + "...")
+
+	if name == "" {
+		err := errors.New("Permission target name can't be empty.")
+		common.LogTxtHandler().Error("Permission target name can't be empty.")
+		return "", err
+	}
+	body, status, err := common.SendRequest("DELETE", permissionPath(name), nil, "")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK && status != http.StatusNoContent {
+		err := errors.New("Unable to delete permission target '" + name + "' (status code " + fmt.Sprint(status) + "): " + string(body))
+		common.LogTxtHandler().Error("Unable to delete permission target '" + name + "' (status code " + fmt.Sprint(status) + ")")
+		return fmt.Sprint(status), err
+	}
+	return "200", nil
+}
+
+func ListPermissionTargets() ([]string, error) {
+	// Lists the names of the permission targets the Identity Token can see
+	common.LogTxtHandler().Info(">>> Getting list of permission targets...")
+
+	body, status, err := common.SendRequest("GET", util.ServerApi + "/v2/security/permissions", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Unable to list permission targets (status code " + fmt.Sprint(status) + ").")
+		common.LogTxtHandler().Error("Unable to list permission targets (status code " + fmt.Sprint(status) + ").")
+		return nil, err
+	}
+
+	var targets []struct {
+		Name	string	`json:"name"`
+	}
+	err = json.Unmarshal(body, &targets)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, err
+	}
+
+	var names []string
+	for _, t := range targets {
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
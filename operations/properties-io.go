@@ -0,0 +1,403 @@
+package operations
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/search"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// PropRecord is a single artifact and its properties as written to, or read from, a property export file
+// Path is relative to the Artifactory root (ex: 'repo-key/folder/artifact.ext')
+type PropRecord struct {
+	Path			string				`json:"path"`
+	Properties		map[string][]string	`json:"properties"`
+}
+
+// PropDrift compares the properties in an import file against those currently on the artifact
+// Missing are in the file but not on the artifact, Changed have different values ('key: current -> file'),
+// and Extra are on the artifact but not in the file (only removed in replace mode)
+// Status is one of "InSync", "Planned", "Updated", "NotFound", or "Failed"
+type PropDrift struct {
+	Path			string
+	Missing			[]string
+	Changed			[]string
+	Extra			[]string
+	Status			string
+	Err				error
+}
+
+func getPropFileFormat(format, filePath string) (string, error) {
+	// Uses the provided format, or works it out from the file extension
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(filePath), ".")
+	}
+	format = common.ConvertToLowercase(format)
+	if format != "csv" && format != "json" {
+		return "", errors.New("Unsupported property file format '" + format + "'. Supported formats are 'csv' and 'json'.")
+	}
+	return format, nil
+}
+
+func listFilesUnderPath(itemPath string) ([]string, error) {
+	// Lists the URI of every file under a repo or folder path ('/repo-key/folder') using the storage file list API
+	requestPath := util.ServerApi + "/storage/" + strings.Trim(itemPath, "/")
+	body, status, err := common.SendRequest("GET", requestPath + "?list&deep=1&listFolders=0", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, errors.New("Unable to list files under path: " + itemPath)
+	}
+
+	var jsonData struct {
+		Files []struct {
+			Uri		string	`json:"uri"`
+			Folder	bool	`json:"folder"`
+		}	`json:"files"`
+	}
+	err = json.Unmarshal(body, &jsonData)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, err
+	}
+
+	var listArtifUris []string
+	for _, f := range jsonData.Files {
+		if !f.Folder {
+			listArtifUris = append(listArtifUris, requestPath + f.Uri)
+		}
+	}
+	return listArtifUris, nil
+}
+
+func getPropRecordsByAql(itemPath string) ([]PropRecord, error) {
+	// Gets every file under the path along with all of its properties in a single AQL query
+	repo, folder, _ := strings.Cut(strings.Trim(itemPath, "/"), "/")
+	criteria := []search.Criterion{search.Eq("repo", repo), search.Eq("type", "file")}
+	if folder != "" {
+		criteria = append(criteria, search.Or(search.Eq("path", folder), search.Match("path", folder + "/*")))
+	}
+	items, _, err := search.ExecuteAql(search.ItemsFind(criteria...).Include("repo", "path", "name", "property.*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var records []PropRecord
+	for _, item := range items {
+		record := PropRecord{Path: item.Repo + "/" + item.Name, Properties: map[string][]string{}}
+		if item.Path != "." {
+			record.Path = item.Repo + "/" + item.Path + "/" + item.Name
+		}
+		for k, v := range item.Properties {
+			record.Properties[k] = v
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func ExportProps(itemPath, format, filePath string) (int, error) {
+	// Writes every file under a repo or folder path ('/repo-key/folder') with its properties to a CSV or JSON file
+	// AQL is tried first; if unavailable, the storage file list is walked and properties are fetched per file
+	// Returns the number of artifacts written
+	common.LogTxtHandler().Info(">>> Exporting Properties Under Path: " + itemPath + "...")
+
+	if itemPath == "" || filePath == "" {
+		err := errors.New("Unable to export properties without a repo/folder path and an output file path.")
+		common.LogTxtHandler().Error("Unable to export properties without a repo/folder path and an output file path.")
+		return 0, err
+	}
+	format, err := getPropFileFormat(format, filePath)
+	if err != nil {
+		common.LogTxtHandler().Error(err.Error())
+		return 0, err
+	}
+
+	records, err := getPropRecordsByAql(itemPath)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Warn("AQL lookup unavailable; falling back to storage listing - " + strErr)
+
+		listArtifUris, err := listFilesUnderPath(itemPath)
+		if err != nil {
+			return 0, err
+		}
+		records = nil
+		if len(listArtifUris) != 0 {
+			details, err := GetArtifactDetailsForList(listArtifUris)
+			if err != nil {
+				return 0, err
+			}
+			for _, d := range details {
+				relPath := strings.TrimPrefix(d.Uri, util.ServerApi + "/storage/")
+				records = append(records, PropRecord{Path: relPath, Properties: d.Properties})
+			}
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Path < records[j].Path
+	})
+
+	outFile, err := os.Create(filePath)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error creating file at target location. " + strErr)
+		return 0, err
+	}
+	defer outFile.Close()
+
+	if format == "json" {
+		encoder := json.NewEncoder(outFile)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(records)
+	} else {
+		err = writePropsCsv(outFile, records)
+	}
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error writing property file. " + strErr)
+		return 0, err
+	}
+
+	common.LogTxtHandler().Info("Exported properties for " + fmt.Sprint(len(records)) + " artifacts to: " + filePath)
+	return len(records), nil
+}
+
+func writePropsCsv(w io.Writer, records []PropRecord) error {
+	// One row per property value: path,key,value
+	// Artifacts without any properties get a single row with an empty key and value
+	csvWriter := csv.NewWriter(w)
+	err := csvWriter.Write([]string{"path", "key", "value"})
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if len(r.Properties) == 0 {
+			err = csvWriter.Write([]string{r.Path, "", ""})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		keys := make([]string, 0, len(r.Properties))
+		for k := range r.Properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, v := range r.Properties[k] {
+				err = csvWriter.Write([]string{r.Path, k, v})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func readPropsCsv(r io.Reader) ([]PropRecord, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var records []PropRecord
+	index := make(map[string]int)
+	for idx, row := range rows {
+		if idx == 0 && len(row) == 3 && row[0] == "path" {
+			continue
+		}
+		if len(row) != 3 || row[0] == "" {
+			return nil, errors.New("Invalid row in property file; expected 'path,key,value': " + strings.Join(row, ","))
+		}
+		pos, found := index[row[0]]
+		if !found {
+			pos = len(records)
+			index[row[0]] = pos
+			records = append(records, PropRecord{Path: row[0], Properties: map[string][]string{}})
+		}
+		if row[1] != "" {
+			records[pos].Properties[row[1]] = append(records[pos].Properties[row[1]], row[2])
+		}
+	}
+	return records, nil
+}
+
+func ReadPropFile(filePath, format string) ([]PropRecord, error) {
+	// Reads a CSV or JSON property file, as written by ExportProps
+	format, err := getPropFileFormat(format, filePath)
+	if err != nil {
+		return nil, err
+	}
+	inFile, err := os.Open(filePath)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error opening property file. " + strErr)
+		return nil, err
+	}
+	defer inFile.Close()
+
+	var records []PropRecord
+	if format == "json" {
+		err = json.NewDecoder(inFile).Decode(&records)
+	} else {
+		records, err = readPropsCsv(inFile)
+	}
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error reading property file. " + strErr)
+		return nil, err
+	}
+	return records, nil
+}
+
+func samePropValues(a, b []string) bool {
+	// Artifactory doesn't keep the order of a multi-valued property, so values are compared as sets
+	a, b = slices.Clone(a), slices.Clone(b)
+	sort.Strings(a)
+	sort.Strings(b)
+	return slices.Equal(a, b)
+}
+
+func comparePropRecord(record PropRecord, current ArtifactDetails) PropDrift {
+	drift := PropDrift{Path: record.Path}
+	for k, v := range record.Properties {
+		if len(v) == 0 {
+			continue
+		}
+		fileVal := strings.Join(v, ",")
+		curVals, found := current.Properties[k]
+		if !found {
+			drift.Missing = append(drift.Missing, k + "=" + fileVal)
+		} else if !samePropValues(curVals, v) {
+			drift.Changed = append(drift.Changed, k + ": " + strings.Join(curVals, ",") + " -> " + fileVal)
+		}
+	}
+	for k, v := range current.Properties {
+		if values, found := record.Properties[k]; !found || len(values) == 0 {
+			drift.Extra = append(drift.Extra, k + "=" + strings.Join(v, ","))
+		}
+	}
+	sort.Strings(drift.Missing)
+	sort.Strings(drift.Changed)
+	sort.Strings(drift.Extra)
+	return drift
+}
+
+func ImportProps(filePath, format string, replace, dryRun bool) ([]PropDrift, error) {
+	// Applies the properties from a CSV or JSON property file (as written by ExportProps) to the artifacts listed in it
+	// In merge mode (replace = false), properties in the file are set and any other properties are left alone
+	// In replace mode, properties on the artifact that aren't in the file are removed as well
+	// Returns the drift between the file and each artifact; with dryRun set to true, nothing is changed
+	common.LogTxtHandler().Info(">>> Importing Properties from: " + filePath + "...")
+
+	records, err := ReadPropFile(filePath, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		err := errors.New("Property file doesn't contain any artifacts.")
+		common.LogTxtHandler().Error("Property file doesn't contain any artifacts.")
+		return nil, err
+	}
+
+	listArtifUris := make([]string, len(records))
+	for idx, r := range records {
+		listArtifUris[idx] = util.ServerApi + "/storage/" + strings.Trim(r.Path, "/")
+	}
+	current, err := GetArtifactDetailsForList(listArtifUris)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]PropDrift, len(records))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(util.MaxWorkers, 1))
+
+	for idx := range records {
+		results[idx] = comparePropRecord(records[idx], current[idx])
+
+		if !current[idx].Found {
+			results[idx].Status = "NotFound"
+			results[idx].Err = errors.New("Artifact not found: " + records[idx].Path)
+			continue
+		}
+
+		needsSet := len(results[idx].Missing) != 0 || len(results[idx].Changed) != 0
+		needsDelete := replace && len(results[idx].Extra) != 0
+		if !needsSet && !needsDelete {
+			results[idx].Status = "InSync"
+			continue
+		}
+		if dryRun {
+			results[idx].Status = "Planned"
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int, needsSet, needsDelete bool) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if needsSet {
+				// Each key is set to its full list of values, so multi-valued properties and values with
+				// special characters (ex: '/', spaces) come through unchanged
+				props := make(map[string][]string)
+				for k, v := range records[idx].Properties {
+					if len(v) != 0 && !samePropValues(current[idx].Properties[k], v) {
+						props[k] = v
+					}
+				}
+				_, err := SetArtifactPropValues(listArtifUris[idx], props)
+				if err != nil {
+					results[idx].Status, results[idx].Err = "Failed", err
+					return
+				}
+			}
+			if needsDelete {
+				var keys []string
+				for _, kv := range results[idx].Extra {
+					key, _, _ := strings.Cut(kv, "=")
+					keys = append(keys, key)
+				}
+				status, err := DeleteArtifactProps(listArtifUris[idx], keys)
+				if err == nil && status != "204" {
+					err = errors.New("Unable to delete properties; status code " + status)
+				}
+				if err != nil {
+					results[idx].Status, results[idx].Err = "Failed", err
+					return
+				}
+			}
+			results[idx].Status = "Updated"
+		}(idx, needsSet, needsDelete)
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Err != nil {
+			strErr := fmt.Sprintf("%v\n", r.Err)
+			common.LogTxtHandler().Error(r.Status + ": " + r.Path + " - " + strErr)
+		} else if r.Status != "InSync" {
+			common.LogTxtHandler().Info(r.Status + ": " + r.Path)
+		}
+	}
+	return results, nil
+}
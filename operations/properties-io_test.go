@@ -0,0 +1,199 @@
+package operations
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// fakePropServer is a minimal stand-in for the Artifactory storage API: it lists files, returns item info, and gets,
+// sets, and deletes properties. AQL isn't supported, so the property functions use their storage API fallbacks.
+type fakePropServer struct {
+	mu		sync.Mutex
+	props	map[string]map[string][]string		// Keyed by 'repo-key/folder/artifact.ext'
+}
+
+func newFakePropServer(t *testing.T, props map[string]map[string][]string) *fakePropServer {
+	f := &fakePropServer{props: make(map[string]map[string][]string)}
+	for artifPath, artifProps := range props {
+		f.props[artifPath] = maps.Clone(artifProps)
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	serverApi := util.ServerApi
+	util.ServerApi = srv.URL + "/api"
+	t.Cleanup(func() { util.ServerApi = serverApi })
+	return f
+}
+
+func (f *fakePropServer) snapshot() map[string]map[string][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := make(map[string]map[string][]string)
+	for artifPath, props := range f.props {
+		copied[artifPath] = maps.Clone(props)
+	}
+	return copied
+}
+
+func (f *fakePropServer) setAll(props map[string][]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for artifPath := range f.props {
+		f.props[artifPath] = maps.Clone(props)
+	}
+}
+
+func (f *fakePropServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	artifPath, found := strings.CutPrefix(r.URL.Path, "/api/storage/")
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	query := r.URL.RawQuery
+
+	switch {
+	case r.Method == "GET" && strings.HasPrefix(query, "list"):
+		var files []map[string]any
+		for p := range f.props {
+			if rel, under := strings.CutPrefix(p, artifPath + "/"); under {
+				files = append(files, map[string]any{"uri": "/" + rel, "folder": false})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"files": files})
+	case r.Method == "GET" && query == "properties":
+		props, exists := f.props[artifPath]
+		if !exists || len(props) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"properties": props})
+	case r.Method == "GET" && query == "":
+		if _, exists := f.props[artifPath]; !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"created": "2024-10-01T12:00:00.000Z", "lastModified": "2024-10-01T12:00:00.000Z"})
+	case r.Method == "PUT" && strings.HasPrefix(query, "properties="):
+		// Separators are only meaningful unescaped; escaped ones arrive percent-encoded with a backslash in front
+		for _, param := range strings.Split(strings.TrimPrefix(query, "properties="), ";") {
+			key, rawValues, _ := strings.Cut(param, "=")
+			var values []string
+			for _, raw := range strings.Split(rawValues, ",") {
+				value, err := url.PathUnescape(raw)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				values = append(values, unescapePropValue(value))
+			}
+			f.props[artifPath][key] = values
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "DELETE" && strings.HasPrefix(query, "properties="):
+		for _, key := range strings.Split(strings.TrimPrefix(query, "properties="), ",") {
+			delete(f.props[artifPath], key)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func unescapePropValue(value string) string {
+	var out strings.Builder
+	escaped := false
+	for _, c := range value {
+		if c == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		out.WriteRune(c)
+	}
+	return out.String()
+}
+
+func sortedProps(props map[string][]string) map[string][]string {
+	sorted := make(map[string][]string)
+	for key, values := range props {
+		values = slices.Clone(values)
+		sort.Strings(values)
+		sorted[key] = values
+	}
+	return sorted
+}
+
+func TestExportImportPropsRoundTrip(t *testing.T) {
+	original := map[string]map[string][]string{
+		"images-local/windows/win2022.ova": {
+			"os":			{"windows", "server"},
+			"description":	{"Windows Server/2022 Datacenter"},
+			"notes":		{"built by a,b; c=d", `C:\images\win2022`},
+			"release":		{"latest-stable"},
+		},
+		"images-local/windows/win2019.ova": {
+			"release":		{"deprecated"},
+		},
+		"images-local/windows/readme.txt": {},
+	}
+
+	for _, format := range []string{"csv", "json"} {
+		t.Run(format, func(t *testing.T) {
+			f := newFakePropServer(t, original)
+
+			filePath := filepath.Join(t.TempDir(), "props." + format)
+			count, err := ExportProps("/images-local/windows", format, filePath)
+			if err != nil {
+				t.Fatalf("ExportProps: %v", err)
+			}
+			if count != len(original) {
+				t.Fatalf("ExportProps wrote %d artifacts, want %d", count, len(original))
+			}
+
+			// Changes made after the export are undone by importing it in replace mode
+			f.setAll(map[string][]string{"stale": {"true"}})
+			results, err := ImportProps(filePath, format, true, false)
+			if err != nil {
+				t.Fatalf("ImportProps: %v", err)
+			}
+			for _, r := range results {
+				if r.Status != "Updated" {
+					t.Errorf("%s: status %s (%v), want Updated", r.Path, r.Status, r.Err)
+				}
+			}
+
+			got := f.snapshot()
+			for artifPath, props := range original {
+				if !maps.EqualFunc(sortedProps(got[artifPath]), sortedProps(props), slices.Equal) {
+					t.Errorf("%s: properties after import = %v, want %v", artifPath, got[artifPath], props)
+				}
+			}
+
+			// Importing the same file again finds nothing to change
+			results, err = ImportProps(filePath, format, true, true)
+			if err != nil {
+				t.Fatalf("ImportProps (second run): %v", err)
+			}
+			for _, r := range results {
+				if r.Status != "InSync" {
+					t.Errorf("%s: status %s after round trip, want InSync (missing %v, changed %v, extra %v)", r.Path, r.Status, r.Missing, r.Changed, r.Extra)
+				}
+			}
+		})
+	}
+}
@@ -6,9 +6,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/search"
 	"github.com/raynaluzier/artifactory-go-sdk/util"
 )
 
@@ -45,7 +52,7 @@ func GetArtifactPropVals(artifUri string, listPropKeys []string) (interface{}, e
 		}
 		request.Header.Add("Authorization", bearer)
 
-		client := &http.Client{}
+		client := common.NewClient()
 		response, err := client.Do(request)
 		if err != nil {
 			strErr := fmt.Sprintf("%v\n", err)
@@ -123,7 +130,7 @@ func GetAllPropsForArtifact(artifUri string) (interface{}, error) {
 		request, err = http.NewRequest("GET", artifUri + "?properties", nil)
 		request.Header.Add("Authorization", bearer)
 
-		client := &http.Client{}
+		client := common.NewClient()
 		response, err := client.Do(request)
 		if err != nil {
 			strErr := fmt.Sprintf("%v\n", err)
@@ -182,10 +189,6 @@ func GetAllPropsForArtifact(artifUri string) (interface{}, error) {
 }
 
 func FilterListByProps(listArtifUris, listKvProps []string) (string, error) {
-	var foundList []string
-	var filteredList []string
-	var structData []map[string]interface{}
-	numProps := len(listKvProps)
 	var foundItem string
 
 	common.LogTxtHandler().Info(">>> Filtering Artifact URIs by Property Keys/Values...")
@@ -194,87 +197,36 @@ func FilterListByProps(listArtifUris, listKvProps []string) (string, error) {
 	}
 
 	if len(listArtifUris) != 0 && len(listKvProps) != 0 {
-		for a := 0; a < len(listArtifUris); a++ {
-			// For each artifact URI in list, get it's properties/values; there can be one or more properties/values assigned
-			artifProps, err := GetAllPropsForArtifact(listArtifUris[a])  // ex return: [{release stable} {testing passed}]
-			if err != nil {
-				common.LogTxtHandler().Debug("No properties returned for artifact: " + listArtifUris[a])
-			
-			} else {
-				// Convert custom data type 'prop' object passed out as interface{} into JSON format
-				jsonBytes, err := json.Marshal(artifProps)
-				if err != nil {
-					strErr := fmt.Sprintf("%v\n", err)
-					common.LogTxtHandler().Error("Error on response. " + strErr)
-				}
-				// Convert the JSON data into a map of arbitrary values to support any type (in this case, our custom 'prop' type)
-				err = json.Unmarshal([]byte(jsonBytes), &structData)
-				if err != nil {
-					strErr := fmt.Sprintf("%v\n", err)
-					common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
-				}
-
-				// For each returned key/value property assigned to the artifact...
-				for idx := 0; idx < len(structData); idx++ {
-					// Convert each pair to a string and format to match the listKvProps input ('key=value')
-					propName := fmt.Sprintf("%v", structData[idx]["Name"]) 
-					propVal := fmt.Sprintf("%v", structData[idx]["Value"])
-					propCompare := propName + "=" + propVal
-
-					for k := 0; k < len(listKvProps); k++ {
-						if propCompare == listKvProps[k] {
-							foundList = append(foundList, listArtifUris[a])
-							common.LogTxtHandler().Debug("Property found: " + listArtifUris[a])
-						}
-					}
-				}
-			}
+		// Properties and created dates for every artifact are fetched up front in bulk, then filtered in memory
+		details, err := GetArtifactDetailsForList(listArtifUris)
+		if err != nil {
+			strErr := fmt.Sprintf("%v\n", err)
+			common.LogTxtHandler().Error("Error getting artifact properties - " + strErr)
+			return "", err
 		}
 
-		if len(foundList) > 1 {
-			// Count the occurance of duplicate artifacts and return a map of the artifact and duplicate count
-			countMap := common.ReturnWithDupCounts(foundList)
-			strCountMap := fmt.Sprintf("%v", countMap)
-			common.LogTxtHandler().Debug("Count of duplicate artifacts and duplication count: " + strCountMap)
-
-			for str, count := range countMap {
-				// If the number of duplicate artifacts found matches the number of input property key/value pairs, add them to a filter list
-				if count == numProps {
-					filteredList = append(filteredList, str)
-					common.LogTxtHandler().Debug("ARTIFACT FOUND WITH MATCHED PROPERTIES: " + str)
-				}
-			}
-			// If only one item resulted in the filtered list, we will return it
-			if len(filteredList) == 1 {
-				foundItem = filteredList[0]
-				common.LogTxtHandler().Info("FOUND ITEM: " + filteredList[0])
-				return foundItem, nil
-			} else if len(filteredList) > 1 {
-				// For each artifact in the filter list, we grab it's 'created' date and add that artifact and date to an array of maps
-				common.LogTxtHandler().Warn("More than one artifact with matching properties was found.")
-				common.LogTxtHandler().Warn("Getting latest artifact...")
-
-				foundItem, err = GetLatestArtifactFromList(filteredList)
-				if err != nil {
-					common.LogTxtHandler().Error("Error getting latest created date.")
-				}
-				return foundItem, nil
-			} else {
-				err := errors.New("Artifacts found with at least one matching property. But no artifact was found with all properties.")
-				common.LogTxtHandler().Error("Artifacts found with at least one matching property. But no artifact was found with all properties.")
-				return "", err
-			}
-		} else if len(foundList) == 1 {
-			if numProps == len(foundList) {
-				foundItem = foundList[0]
-				common.LogTxtHandler().Info("FOUND ARTIFACT: " + foundItem)
-				return foundItem, nil
-			} else {
-				err := errors.New("Artifacts found with at least one matching property. But no artifact was found with all properties.")
-				common.LogTxtHandler().Error("Artifacts found with at least one matching property. But no artifact was found with all properties.")
+		filteredList, partialMatch := FilterDetailsByProps(details, listKvProps)
+
+		// If only one item resulted in the filtered list, we will return it
+		if len(filteredList) == 1 {
+			foundItem = filteredList[0].Uri
+			common.LogTxtHandler().Info("FOUND ITEM: " + foundItem)
+			return foundItem, nil
+		} else if len(filteredList) > 1 {
+			// The created dates were already retrieved with the properties, so the latest can be picked without further requests
+			common.LogTxtHandler().Warn("More than one artifact with matching properties was found.")
+			common.LogTxtHandler().Warn("Getting latest artifact...")
+
+			foundItem, err = GetLatestFromDetails(filteredList)
+			if err != nil {
+				common.LogTxtHandler().Error("Error getting latest created date.")
 				return "", err
 			}
-			
+			return foundItem, nil
+		} else if partialMatch {
+			err := errors.New("Artifacts found with at least one matching property. But no artifact was found with all properties.")
+			common.LogTxtHandler().Error("Artifacts found with at least one matching property. But no artifact was found with all properties.")
+			return "", err
 		} else {
 			err := errors.New("No matching artifacts were found.")
 			common.LogTxtHandler().Error("No matching artifacts were found.")
@@ -286,6 +238,10 @@ func FilterListByProps(listArtifUris, listKvProps []string) (string, error) {
 
 func SetArtifactProps(artifUri string, listKvProps []string) (string, error) {
 	// Inputs are CASE SENSITIVE
+	// Request state is kept local so this can be called concurrently (ex: bulk updates)
+	var request *http.Request
+	var err error
+	var statusCode string
 	bearer := common.SetBearer(util.Token)
 	requestPath := artifUri + "?properties="
 	common.LogTxtHandler().Info(">>> Setting Specified Property(ies) for: " + artifUri)
@@ -297,6 +253,12 @@ func SetArtifactProps(artifUri string, listKvProps []string) (string, error) {
 		return "", err
 
 	} else {
+		// Properties are checked against any schema registered for the artifact's path before they're set
+		err = ValidateProps(artifUri, listKvProps, false)
+		if err != nil {
+			return "", err
+		}
+
 		if artifUri != "" && len(listKvProps) != 0 {
 			// Determines whether we will format a list of property keys/values first, or pass a single property key/value pair
 			// before making the API call
@@ -319,7 +281,7 @@ func SetArtifactProps(artifUri string, listKvProps []string) (string, error) {
 			}
 			request.Header.Add("Authorization", bearer)
 			
-			client := &http.Client{}
+			client := common.NewClient()
 			response, err := client.Do(request)
 			if err != nil {
 				strErr := fmt.Sprintf("%v\n", err)
@@ -360,9 +322,68 @@ func SetArtifactProps(artifUri string, listKvProps []string) (string, error) {
 	return statusCode, nil
 }
 
+// Characters Artifactory treats as separators in a '?properties=' value; they're sent with a backslash in front
+var propValueEscaper = strings.NewReplacer("\\", "\\\\", ",", "\\,", "|", "\\|", "=", "\\=", ";", "\\;")
+
+func SetArtifactPropValues(artifUri string, props map[string][]string) (string, error) {
+	// Sets each property key to the given list of values, replacing any values the key already has
+	// Unlike SetArtifactProps, values may hold multiple entries and any characters (ex: '/', spaces, commas); they're
+	// escaped as Artifactory requires. Keys still can't contain special characters.
+	common.LogTxtHandler().Info(">>> Setting Property Values for: " + artifUri)
+
+	if artifUri == "" || len(props) == 0 {
+		err := errors.New("Unable to set Artifact properties without artifact's URI and one or more property names/values.")
+		common.LogTxtHandler().Error("Unable to set Artifact properties without artifact's URI and one or more property names/values.")
+		return "", err
+	}
+
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var kvProps, params []string
+	for _, key := range keys {
+		if key == "" || len(props[key]) == 0 || common.ContainsSpecialChars([]string{key}) {
+			err := errors.New("Property '" + key + "' must have a name without special characters and at least one value.")
+			common.LogTxtHandler().Error("Property '" + key + "' must have a name without special characters and at least one value.")
+			return "", err
+		}
+		var escaped []string
+		for _, v := range props[key] {
+			kvProps = append(kvProps, key + "=" + v)
+			escaped = append(escaped, strings.ReplaceAll(url.QueryEscape(propValueEscaper.Replace(v)), "+", "%20"))
+		}
+		params = append(params, key + "=" + strings.Join(escaped, ","))
+	}
+
+	// Properties are checked against any schema registered for the artifact's path before they're set
+	err := ValidateProps(artifUri, kvProps, false)
+	if err != nil {
+		return "", err
+	}
+
+	_, status, err := common.SendRequest("PUT", artifUri + "?properties=" + strings.Join(params, ";"), nil, "")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusNoContent {
+		err := errors.New("Unable to set properties for: " + artifUri + " (status code " + strconv.Itoa(status) + ")")
+		common.LogTxtHandler().Error("Unable to set properties for: " + artifUri + " (status code " + strconv.Itoa(status) + ")")
+		return strconv.Itoa(status), err
+	}
+	common.LogTxtHandler().Info("Request completed successfully")
+	return "204", nil
+}
+
 func DeleteArtifactProps(artifUri string, listProps []string) (string, error) {
 	// Inputs are CASE SENSITIVE
 	// If a property is provided that doesn't exist (which includes incorrectly cased properties), the API ignores this and will return a successful response
+	// Request state is kept local so this can be called concurrently (ex: bulk updates)
+	var request *http.Request
+	var err error
+	var statusCode string
 	bearer := common.SetBearer(util.Token)
 	requestPath := artifUri + "?properties="
 	common.LogTxtHandler().Info(">>> Deleting Specified Property(ies) for Artifact: " + artifUri)
@@ -387,7 +408,7 @@ func DeleteArtifactProps(artifUri string, listProps []string) (string, error) {
 		}
 		request.Header.Add("Authorization", bearer)
 		
-		client := &http.Client{}
+		client := common.NewClient()
 		response, err := client.Do(request)
 		if err != nil {
 			strErr := fmt.Sprintf("%v\n", err)
@@ -425,4 +446,518 @@ func DeleteArtifactProps(artifUri string, listProps []string) (string, error) {
 	}
 
 	return statusCode, nil
-}
\ No newline at end of file
+}
+
+// ArtifactDetails holds the created/last modified dates and properties of an artifact, gathered in bulk so that
+// filtering and sorting can be done in memory instead of making a request per artifact
+// Found is false if the artifact doesn't exist; its dates are then zero and it has no properties
+type ArtifactDetails struct {
+	Uri				string
+	Found			bool
+	Created			time.Time
+	LastModified	time.Time
+	Properties		map[string][]string
+}
+
+func GetArtifactDetailsForList(listArtifUris []string) ([]ArtifactDetails, error) {
+	// Fetches created/last modified dates and properties for a whole list of artifact URIs
+	// A single AQL query is tried first (batched to keep the query size reasonable); if AQL is unavailable,
+	// falls back to fetching each artifact with a bounded number of concurrent requests
+	common.LogTxtHandler().Info(">>> Getting Details for List of Artifacts...")
+
+	if len(listArtifUris) == 0 {
+		err := errors.New("List of artifacts cannot be empty.")
+		common.LogTxtHandler().Error("List of artifacts cannot be empty.")
+		return nil, err
+	}
+
+	details := make([]ArtifactDetails, len(listArtifUris))
+	for idx, uri := range listArtifUris {
+		details[idx] = ArtifactDetails{Uri: uri, Properties: map[string][]string{}}
+	}
+
+	err := fetchDetailsByAql(details)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Warn("AQL lookup unavailable; falling back to individual requests - " + strErr)
+		// Earlier batches may have succeeded; starting over keeps their properties from being added twice
+		for idx := range details {
+			details[idx] = ArtifactDetails{Uri: details[idx].Uri, Properties: map[string][]string{}}
+		}
+		err = fetchDetailsConcurrently(details)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return details, nil
+}
+
+func fetchDetailsByAql(details []ArtifactDetails) error {
+	// Looks up every artifact in the list with AQL (see search.GetItemsForList), with all of its properties included
+	// Matches the results back to the input by repo/path/name
+	listArtifUris := make([]string, len(details))
+	for idx := range details {
+		listArtifUris[idx] = details[idx].Uri
+	}
+	items, err := search.GetItemsForList(listArtifUris, true)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]search.Item)
+	for _, item := range items {
+		found[item.Repo + "/" + item.Path + "/" + item.Name] = item
+	}
+	for idx := range details {
+		repo, itemPath, name := common.ParseArtifUriForRepoPathName("", details[idx].Uri)
+		item, exists := found[repo + "/" + itemPath + "/" + name]
+		if !exists {
+			continue
+		}
+		details[idx].Found = true
+		details[idx].Created = item.Created
+		details[idx].LastModified = item.Modified
+		for k, v := range item.Properties {
+			details[idx].Properties[k] = append(details[idx].Properties[k], v...)
+		}
+	}
+	return nil
+}
+
+func fetchDetailsConcurrently(details []ArtifactDetails) error {
+	// Fetches the item info and properties of each artifact with at most util.MaxWorkers requests in flight
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	workers := max(util.MaxWorkers, 1)
+	sem := make(chan struct{}, workers)
+
+	for idx := range details {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := fetchArtifactDetails(&details[idx])
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(idx)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func fetchArtifactDetails(details *ArtifactDetails) error {
+	// Gets the created/last modified dates and all properties for a single artifact
+	info, err := GetItemInfo(details.Uri)
+	if errors.Is(err, ErrItemNotFound) {
+		// Matches the AQL lookup, where an artifact that doesn't exist is simply left without details
+		common.LogTxtHandler().Warn("Artifact not found: " + details.Uri)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	details.Found = true
+	details.Created = info.Created
+	details.LastModified = info.LastModified
+
+	body, status, err := common.SendRequest("GET", details.Uri + "?properties", nil, "")
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		// An artifact without any properties returns 404; this isn't an error condition
+		common.LogTxtHandler().Debug("No properties returned for artifact: " + details.Uri)
+		return nil
+	}
+
+	var result struct {
+		Properties	map[string][]string	`json:"properties"`
+	}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return err
+	}
+	for k, v := range result.Properties {
+		details.Properties[k] = append(details.Properties[k], v...)
+	}
+	return nil
+}
+
+func (d ArtifactDetails) HasProp(kvProp string) bool {
+	// Checks whether the artifact has the given property; takes 'key=value' or just 'key'
+	// A multi-valued property matches if any of its values match
+	key, value, hasValue := strings.Cut(kvProp, "=")
+	values, found := d.Properties[key]
+	if !found {
+		return false
+	}
+	if !hasValue {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func FilterDetailsByProps(details []ArtifactDetails, listKvProps []string) ([]ArtifactDetails, bool) {
+	// Returns the artifacts that have ALL of the given property key/values
+	// Also reports whether any artifact matched at least one of them, which is used for error reporting
+	var filtered []ArtifactDetails
+	partialMatch := false
+
+	for _, d := range details {
+		numMatched := 0
+		for _, kv := range listKvProps {
+			if d.HasProp(kv) {
+				numMatched++
+			}
+		}
+		if numMatched > 0 {
+			partialMatch = true
+		}
+		if numMatched == len(listKvProps) {
+			filtered = append(filtered, d)
+			common.LogTxtHandler().Debug("ARTIFACT FOUND WITH MATCHED PROPERTIES: " + d.Uri)
+		}
+	}
+	return filtered, partialMatch
+}
+
+func GetLatestFromDetails(details []ArtifactDetails) (string, error) {
+	// Returns the URI of the most recently created artifact in the list
+	return GetLatestFromDetailsBy(details, ByCreated)
+}
+
+// ErrPropConflict is returned when a conditional property update finds a different value than expected,
+// either before the change is applied or when re-verifying afterwards
+var ErrPropConflict = errors.New("Property value conflict")
+
+func getPropValues(artifUri, propKey string) ([]string, bool, error) {
+	// Returns the current values of a single property and whether the property exists on the artifact
+	body, status, err := common.SendRequestNoCache("GET", artifUri + "?properties=" + propKey, nil, "")
+	if err != nil {
+		return nil, false, err
+	}
+	if status == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if status != http.StatusOK {
+		return nil, false, errors.New("Unable to get property '" + propKey + "' for artifact: " + artifUri)
+	}
+
+	var result struct {
+		Properties	map[string][]string	`json:"properties"`
+	}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, false, err
+	}
+	values, found := result.Properties[propKey]
+	return values, found, nil
+}
+
+func propValueMatches(values []string, found bool, expected string) bool {
+	// An empty expected value means the property must not be set
+	if expected == "" {
+		return !found
+	}
+	return found && strings.Join(values, ",") == expected
+}
+
+func SetArtifactPropIfMatch(artifUri, propKey, expectedVal, newVal string) (string, error) {
+	// Compare-and-set for a single property: only applies the change if the property's current value still
+	// equals the expected value, then re-reads the property to verify the change stuck
+	// An empty expected value means the property must not currently be set; an empty new value removes the property
+	// Returns ErrPropConflict if the value didn't match before or after the update
+	common.LogTxtHandler().Info(">>> Conditionally Setting Property '" + propKey + "' for: " + artifUri)
+
+	if artifUri == "" || propKey == "" {
+		err := errors.New("Unable to set Artifact property without artifact's URI and a property name.")
+		common.LogTxtHandler().Error("Unable to set Artifact property without artifact's URI and a property name.")
+		return "", err
+	}
+
+	values, found, err := getPropValues(artifUri, propKey)
+	if err != nil {
+		return "", err
+	}
+	if !propValueMatches(values, found, expectedVal) {
+		common.LogTxtHandler().Warn("Property '" + propKey + "' is currently '" + strings.Join(values, ",") + "', expected '" + expectedVal + "'")
+		return "", fmt.Errorf("%w: '%s' on %s is '%s', expected '%s'", ErrPropConflict, propKey, artifUri, strings.Join(values, ","), expectedVal)
+	}
+
+	var status string
+	if newVal == "" {
+		status, err = DeleteArtifactProps(artifUri, []string{propKey})
+	} else {
+		status, err = SetArtifactProps(artifUri, []string{propKey + "=" + newVal})
+	}
+	if err != nil {
+		return "", err
+	}
+	if status != "204" {
+		err := errors.New("Unable to update property '" + propKey + "' for artifact: " + artifUri)
+		common.LogTxtHandler().Error("Unable to update property '" + propKey + "' for artifact: " + artifUri)
+		return status, err
+	}
+
+	// Another writer may have changed the property between our check and our update
+	values, found, err = getPropValues(artifUri, propKey)
+	if err != nil {
+		return "", err
+	}
+	if !propValueMatches(values, found, newVal) {
+		common.LogTxtHandler().Warn("Property '" + propKey + "' changed during update; now '" + strings.Join(values, ",") + "'")
+		return "", fmt.Errorf("%w: '%s' on %s is '%s' after update, expected '%s'", ErrPropConflict, propKey, artifUri, strings.Join(values, ","), newVal)
+	}
+
+	common.LogTxtHandler().Info("Property '" + propKey + "' updated and verified.")
+	return status, nil
+}
+
+func MoveArtifactProp(fromUri, toUri, propKey, propVal string) (string, error) {
+	// Moves a 'label' property (ex: release=latest-stable) from the artifact currently holding it to a new artifact
+	// The label is removed from the current holder only if it still has the expected value, then set on the new artifact
+	// If setting it on the new artifact fails, the label is restored on the original holder
+	// fromUri may be empty if no artifact currently holds the label
+	common.LogTxtHandler().Info(">>> Moving Property '" + propKey + "=" + propVal + "' to: " + toUri)
+
+	if toUri == "" || propKey == "" || propVal == "" {
+		err := errors.New("Unable to move property without the target artifact's URI and a property name and value.")
+		common.LogTxtHandler().Error("Unable to move property without the target artifact's URI and a property name and value.")
+		return "", err
+	}
+
+	if fromUri != "" {
+		_, err := SetArtifactPropIfMatch(fromUri, propKey, propVal, "")
+		if err != nil {
+			strErr := fmt.Sprintf("%v\n", err)
+			common.LogTxtHandler().Error("Unable to remove property from current holder - " + strErr)
+			return "", err
+		}
+	}
+
+	// The target may already carry an older value for the key, so we compare against whatever it has now
+	values, found, err := getPropValues(toUri, propKey)
+	if err == nil {
+		currentVal := ""
+		if found {
+			currentVal = strings.Join(values, ",")
+		}
+		_, err = SetArtifactPropIfMatch(toUri, propKey, currentVal, propVal)
+	}
+
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Unable to set property on new artifact - " + strErr)
+
+		if fromUri != "" {
+			common.LogTxtHandler().Warn("Rolling back; restoring property on: " + fromUri)
+			_, rbErr := SetArtifactPropIfMatch(fromUri, propKey, "", propVal)
+			if rbErr != nil {
+				strRbErr := fmt.Sprintf("%v\n", rbErr)
+				common.LogTxtHandler().Error("Rollback failed - " + strRbErr)
+				return "", errors.Join(err, rbErr)
+			}
+		}
+		return "", err
+	}
+
+	common.LogTxtHandler().Info("Property '" + propKey + "=" + propVal + "' moved to: " + toUri)
+	return "204", nil
+}
+
+// Actions supported by a PropMutation
+const (
+	PropSet		= "set"
+	PropDelete	= "delete"
+	PropRename	= "rename"
+)
+
+// PropMutation describes one change to apply to an artifact's properties
+// 'set' uses Key and Value, 'delete' uses Key, and 'rename' moves the values of Key to NewKey
+type PropMutation struct {
+	Action		string
+	Key			string
+	Value		string
+	NewKey		string
+}
+
+// PropMutationResult reports what was (or, for a dry run, would be) changed on a single artifact
+// Status is one of "Planned", "Success", "Skipped", or "Failed"
+type PropMutationResult struct {
+	Uri			string
+	Changes		[]string
+	Status		string
+	Err			error
+}
+
+func planPropMutations(d ArtifactDetails, mutations []PropMutation) (map[string][]string, []string, []string) {
+	// Works out which properties need to be set and deleted on the artifact to apply the mutations, based on
+	// its current properties; mutations that wouldn't change anything are left out
+	// Mutations are applied in order to a working copy, so later ones see the results of earlier ones
+	// (ex: renaming 'a' to 'b' then setting 'a' again)
+	working := make(map[string][]string, len(d.Properties))
+	for key, values := range d.Properties {
+		working[key] = slices.Clone(values)
+	}
+	var changes []string
+
+	for _, m := range mutations {
+		current, found := working[m.Key]
+		switch m.Action {
+		case PropSet:
+			if found && len(current) == 1 && current[0] == m.Value {
+				continue
+			}
+			working[m.Key] = []string{m.Value}
+			changes = append(changes, "set " + m.Key + "=" + m.Value)
+		case PropDelete:
+			if !found {
+				continue
+			}
+			delete(working, m.Key)
+			changes = append(changes, "delete " + m.Key)
+		case PropRename:
+			if !found || m.Key == m.NewKey {
+				continue
+			}
+			working[m.NewKey] = current
+			delete(working, m.Key)
+			changes = append(changes, "rename " + m.Key + " -> " + m.NewKey)
+		}
+	}
+
+	// Only the difference between the original and the end result is sent
+	setProps := make(map[string][]string)
+	var deleteProps []string
+	for key, values := range working {
+		if original, found := d.Properties[key]; !found || !slices.Equal(original, values) {
+			setProps[key] = values
+		}
+	}
+	for key := range d.Properties {
+		if _, found := working[key]; !found {
+			deleteProps = append(deleteProps, key)
+		}
+	}
+	sort.Strings(deleteProps)
+	if len(setProps) == 0 && len(deleteProps) == 0 {
+		return nil, nil, nil
+	}
+	return setProps, deleteProps, changes
+}
+
+func validatePropMutations(mutations []PropMutation) error {
+	if len(mutations) == 0 {
+		return errors.New("Unable to update Artifact properties without one or more property changes.")
+	}
+	for _, m := range mutations {
+		switch m.Action {
+		case PropSet:
+			if m.Key == "" || m.Value == "" {
+				return errors.New("Property 'set' requires a property name and value.")
+			}
+		case PropDelete:
+			if m.Key == "" {
+				return errors.New("Property 'delete' requires a property name.")
+			}
+		case PropRename:
+			if m.Key == "" || m.NewKey == "" {
+				return errors.New("Property 'rename' requires the current and new property names.")
+			}
+		default:
+			return errors.New("Unsupported property action '" + m.Action + "'. Supported actions are 'set', 'delete', and 'rename'.")
+		}
+	}
+	return nil
+}
+
+func ApplyPropMutations(listArtifUris []string, mutations []PropMutation, dryRun bool) ([]PropMutationResult, error) {
+	// Applies the same set of property changes to every artifact in the list
+	// Current properties are fetched in bulk first, so each artifact only gets the changes it actually needs
+	// When dryRun is true, nothing is changed and each result lists the planned changes
+	// Artifacts are updated with at most util.MaxWorkers requests in flight; a failure on one artifact doesn't stop the others
+	common.LogTxtHandler().Info(">>> Applying Property Changes to List of Artifacts...")
+
+	err := validatePropMutations(mutations)
+	if err != nil {
+		common.LogTxtHandler().Error(err.Error())
+		return nil, err
+	}
+
+	details, err := GetArtifactDetailsForList(listArtifUris)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]PropMutationResult, len(details))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(util.MaxWorkers, 1))
+
+	for idx := range details {
+		setProps, deleteProps, changes := planPropMutations(details[idx], mutations)
+		results[idx] = PropMutationResult{Uri: details[idx].Uri, Changes: changes}
+
+		if len(changes) == 0 {
+			results[idx].Status = "Skipped"
+			continue
+		}
+		if dryRun {
+			results[idx].Status = "Planned"
+			common.LogTxtHandler().Info("DRY RUN: " + details[idx].Uri + " - " + strings.Join(changes, "; "))
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// New values are set before old keys are removed so a rename never loses data
+			if len(setProps) != 0 {
+				_, err := SetArtifactPropValues(results[idx].Uri, setProps)
+				if err != nil {
+					results[idx].Status, results[idx].Err = "Failed", err
+					return
+				}
+			}
+			if len(deleteProps) != 0 {
+				status, err := DeleteArtifactProps(results[idx].Uri, deleteProps)
+				if err == nil && status != "204" {
+					err = errors.New("Unable to delete properties; status code " + status)
+				}
+				if err != nil {
+					results[idx].Status, results[idx].Err = "Failed", err
+					return
+				}
+			}
+			results[idx].Status = "Success"
+		}(idx)
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Err != nil {
+			strErr := fmt.Sprintf("%v\n", r.Err)
+			common.LogTxtHandler().Error("FAILED: " + r.Uri + " - " + strErr)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,445 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+)
+
+// RepoConfigFile describes the desired repos and permission targets
+// Prune lists key/name patterns (ex: 'images-*') this file manages; live repos and permission targets that match
+// but aren't in the file are planned for deletion. Without it, nothing is ever deleted.
+type RepoConfigFile struct {
+	Local				[]LocalRepo			`json:"local,omitempty"`
+	Remote				[]RemoteRepo		`json:"remote,omitempty"`
+	Virtual				[]VirtualRepo		`json:"virtual,omitempty"`
+	PermissionTargets	[]PermissionTarget	`json:"permissionTargets,omitempty"`
+	Prune				[]string			`json:"prune,omitempty"`
+}
+
+// Actions in a configuration plan
+const (
+	ConfigCreate	= "create"
+	ConfigUpdate	= "update"
+	ConfigDelete	= "delete"
+	ConfigNoChange	= "none"
+	ConfigConflict	= "conflict"	// The change can't be made in place (ex: the repo type or package type differs)
+)
+
+// Kinds of objects in a configuration plan
+const (
+	ConfigRepo			= "repo"
+	ConfigPermission	= "permission"
+)
+
+// ConfigChange is one step of a configuration plan, and after applying, its outcome
+// Status is one of "Planned", "Success", "Skipped", or "Failed"
+type ConfigChange struct {
+	Kind		string
+	Key			string
+	Action		string
+	Changes		[]string
+	Status		string
+	Err			error
+	repo		RepoConfig
+	permission	*PermissionTarget
+}
+
+func ReadRepoConfigFile(filePath string) (RepoConfigFile, error) {
+	// Reads the desired repo configuration from a JSON or YAML ('.yaml' or '.yml') file
+	var config RepoConfigFile
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error reading repo configuration file. " + strErr)
+		return config, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext == ".yaml" || ext == ".yml" {
+		// YAML is converted to JSON, so both are decoded (and checked for unknown settings) the same way
+		value, err := parseYaml(string(data))
+		if err == nil {
+			value, err = yamlToJson(value, reflect.TypeOf(config))
+		}
+		if err == nil {
+			data, err = json.Marshal(value)
+		}
+		if err != nil {
+			strErr := fmt.Sprintf("%v\n", err)
+			common.LogTxtHandler().Error("Could not parse YAML repo configuration file - " + strErr)
+			return config, err
+		}
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()        // Catches misspelled settings, which would otherwise be silently ignored
+	err = decoder.Decode(&config)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal repo configuration file - " + strErr)
+		return config, err
+	}
+	return config, nil
+}
+
+func (c RepoConfigFile) repos() []RepoConfig {
+	// Local and remote repos come before virtual repos, which may include them
+	var repos []RepoConfig
+	for _, r := range c.Local {
+		repos = append(repos, r)
+	}
+	for _, r := range c.Remote {
+		repos = append(repos, r)
+	}
+	for _, r := range c.Virtual {
+		repos = append(repos, r)
+	}
+	return repos
+}
+
+func (c RepoConfigFile) isPruned(key string) bool {
+	for _, pattern := range c.Prune {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c RepoConfigFile) validate() error {
+	seen := make(map[string]bool)
+	for _, r := range c.repos() {
+		err := checkRepoConfig(r)
+		if err != nil {
+			return err
+		}
+		if seen[r.GetKey()] {
+			err := errors.New("Repo '" + r.GetKey() + "' is defined more than once.")
+			common.LogTxtHandler().Error("Repo '" + r.GetKey() + "' is defined more than once.")
+			return err
+		}
+		seen[r.GetKey()] = true
+	}
+
+	seen = make(map[string]bool)
+	for _, p := range c.PermissionTargets {
+		if p.Name == "" {
+			err := errors.New("Permission target name can't be empty.")
+			common.LogTxtHandler().Error("Permission target name can't be empty.")
+			return err
+		}
+		if seen[p.Name] {
+			err := errors.New("Permission target '" + p.Name + "' is defined more than once.")
+			common.LogTxtHandler().Error("Permission target '" + p.Name + "' is defined more than once.")
+			return err
+		}
+		seen[p.Name] = true
+	}
+
+	for _, pattern := range c.Prune {
+		if _, err := path.Match(pattern, ""); err != nil {
+			err := errors.New("Invalid prune pattern '" + pattern + "'.")
+			common.LogTxtHandler().Error("Invalid prune pattern '" + pattern + "'.")
+			return err
+		}
+	}
+	return nil
+}
+
+func diffRepoConfig(desired, live RepoConfig) ([]string, bool, error) {
+	// Lists the settings in the desired config that differ from the live config
+	// Only settings set in the desired config are compared (unset flags and lists aren't sent, so aren't changed);
+	// the password is never compared as it isn't returned as-is
+	// The bool is false if the change can't be made in place
+	if desired.RepoType() != live.RepoType() {
+		return []string{"repo type: " + live.RepoType() + " -> " + desired.RepoType()}, false, nil
+	}
+
+	desiredData, err := marshalRepoConfig(desired)
+	if err != nil {
+		return nil, false, err
+	}
+	liveData, err := marshalRepoConfig(live)
+	if err != nil {
+		return nil, false, err
+	}
+	var desiredFields, liveFields map[string]json.RawMessage
+	if err = json.Unmarshal(desiredData, &desiredFields); err != nil {
+		return nil, false, err
+	}
+	if err = json.Unmarshal(liveData, &liveFields); err != nil {
+		return nil, false, err
+	}
+
+	var keys []string
+	for k := range desiredFields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var changes []string
+	inPlace := true
+	for _, k := range keys {
+		if k == "key" || k == "password" || k == "rclass" || string(compactJson(desiredFields[k])) == "null" {
+			continue
+		}
+		liveVal, found := liveFields[k]
+		if !found {
+			liveVal = json.RawMessage("null")
+		}
+		if string(compactJson(liveVal)) == string(compactJson(desiredFields[k])) {
+			continue
+		}
+		if k == "packageType" {
+			inPlace = false
+		}
+		changes = append(changes, k + ": " + string(compactJson(liveVal)) + " -> " + string(compactJson(desiredFields[k])))
+	}
+	return changes, inPlace, nil
+}
+
+func compactJson(value json.RawMessage) []byte {
+	var v any
+	if json.Unmarshal(value, &v) != nil {
+		return value
+	}
+	data, _ := json.Marshal(v)
+	return data
+}
+
+func normalizePermission(target PermissionTarget) PermissionTarget {
+	// Sorts lists so that ordering differences (ex: of actions) aren't reported as changes
+	if target.Repo == nil {
+		return target
+	}
+	repo := *target.Repo
+	repo.Repositories = slices.Sorted(slices.Values(repo.Repositories))
+	repo.IncludePatterns = slices.Sorted(slices.Values(repo.IncludePatterns))
+	repo.ExcludePatterns = slices.Sorted(slices.Values(repo.ExcludePatterns))
+	sortActions := func(actions map[string][]string) map[string][]string {
+		if len(actions) == 0 {
+			return nil
+		}
+		sorted := make(map[string][]string)
+		for k, v := range actions {
+			sorted[k] = slices.Sorted(slices.Values(v))
+		}
+		return sorted
+	}
+	repo.Actions = PermissionActions{Users: sortActions(repo.Actions.Users), Groups: sortActions(repo.Actions.Groups)}
+	target.Repo = &repo
+	return target
+}
+
+func diffPermission(desired, live PermissionTarget) []string {
+	desired, live = normalizePermission(desired), normalizePermission(live)
+	if desired.Repo == nil {
+		desired.Repo = &PermissionRepo{}
+	}
+	if live.Repo == nil {
+		live.Repo = &PermissionRepo{}
+	}
+
+	var changes []string
+	compare := func(name string, liveVal, desiredVal any) {
+		liveJson, _ := json.Marshal(liveVal)
+		desiredJson, _ := json.Marshal(desiredVal)
+		if string(liveJson) != string(desiredJson) {
+			changes = append(changes, name + ": " + string(liveJson) + " -> " + string(desiredJson))
+		}
+	}
+	compare("repositories", live.Repo.Repositories, desired.Repo.Repositories)
+	compare("include-patterns", live.Repo.IncludePatterns, desired.Repo.IncludePatterns)
+	compare("exclude-patterns", live.Repo.ExcludePatterns, desired.Repo.ExcludePatterns)
+	compare("users", live.Repo.Actions.Users, desired.Repo.Actions.Users)
+	compare("groups", live.Repo.Actions.Groups, desired.Repo.Actions.Groups)
+	return changes
+}
+
+func PlanRepoConfig(desired RepoConfigFile) ([]ConfigChange, error) {
+	// Compares the desired configuration against the live repos and permission targets, and lists the changes needed
+	// Nothing is changed; pass the plan to 'ApplyRepoConfig' to make the changes
+	var plan []ConfigChange
+
+	common.LogTxtHandler().Info(">>> Planning repo configuration changes...")
+
+	err := desired.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	liveRepos, err := ListRepoSummaries()
+	if err != nil {
+		return nil, err
+	}
+	liveKeys := make(map[string]RepoSummary)
+	for _, r := range liveRepos {
+		liveKeys[r.Key] = r
+	}
+
+	desiredKeys := make(map[string]bool)
+	for _, repo := range desired.repos() {
+		desiredKeys[repo.GetKey()] = true
+		change := ConfigChange{Kind: ConfigRepo, Key: repo.GetKey(), Status: "Planned", repo: repo}
+
+		summary, exists := liveKeys[repo.GetKey()]
+		switch {
+		case !exists:
+			change.Action = ConfigCreate
+			change.Changes = []string{"create " + repo.RepoType() + " repo"}
+		case strings.EqualFold(summary.Type, "federated"):
+			change.Action = ConfigConflict
+			change.Changes = []string{"repo type: federated -> " + repo.RepoType()}
+		default:
+			live, err := GetRepo(repo.GetKey())
+			if err != nil {
+				return nil, err
+			}
+			changes, inPlace, err := diffRepoConfig(repo, live)
+			if err != nil {
+				return nil, err
+			}
+			change.Changes = changes
+			switch {
+			case !inPlace:
+				change.Action = ConfigConflict
+			case len(changes) == 0:
+				change.Action = ConfigNoChange
+			default:
+				change.Action = ConfigUpdate
+			}
+		}
+		plan = append(plan, change)
+	}
+
+	desiredNames := make(map[string]bool)
+	for idx := range desired.PermissionTargets {
+		target := desired.PermissionTargets[idx]
+		desiredNames[target.Name] = true
+		change := ConfigChange{Kind: ConfigPermission, Key: target.Name, Status: "Planned", permission: &target}
+
+		live, exists, err := GetPermissionTarget(target.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			change.Action = ConfigCreate
+			change.Changes = []string{"create permission target"}
+		} else if change.Changes = diffPermission(target, live); len(change.Changes) == 0 {
+			change.Action = ConfigNoChange
+		} else {
+			change.Action = ConfigUpdate
+		}
+		plan = append(plan, change)
+	}
+
+	if len(desired.Prune) != 0 {
+		// Permission targets are deleted before the repos they refer to, and virtual repos before the repos they include
+		// Permission targets are only pruned if the file manages any, as listing them needs admin access
+		if len(desired.PermissionTargets) != 0 {
+			names, err := ListPermissionTargets()
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range names {
+				if !desiredNames[name] && desired.isPruned(name) {
+					plan = append(plan, ConfigChange{Kind: ConfigPermission, Key: name, Action: ConfigDelete, Status: "Planned", Changes: []string{"delete permission target"}})
+				}
+			}
+		}
+		var virtualDeletes, otherDeletes []ConfigChange
+		for _, r := range liveRepos {
+			if desiredKeys[r.Key] || !desired.isPruned(r.Key) {
+				continue
+			}
+			change := ConfigChange{Kind: ConfigRepo, Key: r.Key, Action: ConfigDelete, Status: "Planned", Changes: []string{"delete " + strings.ToLower(r.Type) + " repo and everything in it"}}
+			if strings.EqualFold(r.Type, RepoVirtual) {
+				virtualDeletes = append(virtualDeletes, change)
+			} else {
+				otherDeletes = append(otherDeletes, change)
+			}
+		}
+		plan = append(plan, virtualDeletes...)
+		plan = append(plan, otherDeletes...)
+	}
+
+	for _, c := range plan {
+		if c.Action != ConfigNoChange {
+			common.LogTxtHandler().Info("PLAN: " + c.Action + " " + c.Kind + " '" + c.Key + "': " + strings.Join(c.Changes, "; "))
+		}
+	}
+	return plan, nil
+}
+
+func ApplyRepoConfig(plan []ConfigChange, allowDelete bool) ([]ConfigChange, error) {
+	// Makes the changes in a plan from 'PlanRepoConfig', in order
+	// Deletes are skipped unless allowDelete is true; conflicts are reported as failed and never applied
+	// A failed change doesn't stop the rest; returns an error if any change failed
+	common.LogTxtHandler().Info(">>> Applying repo configuration changes...")
+
+	results := slices.Clone(plan)
+	failed := 0
+	for idx := range results {
+		change := &results[idx]
+		var err error
+
+		switch change.Action {
+		case ConfigNoChange:
+			change.Status = "Skipped"
+			continue
+		case ConfigConflict:
+			err = errors.New(change.Kind + " '" + change.Key + "' can't be changed in place: " + strings.Join(change.Changes, "; "))
+		case ConfigDelete:
+			if !allowDelete {
+				common.LogTxtHandler().Warn("Skipping delete of " + change.Kind + " '" + change.Key + "'; deletes weren't allowed.")
+				change.Status = "Skipped"
+				continue
+			}
+			if change.Kind == ConfigRepo {
+				_, err = DeleteRepo(change.Key)
+			} else {
+				_, err = DeletePermissionTarget(change.Key)
+			}
+		case ConfigCreate, ConfigUpdate:
+			switch {
+			case change.Kind == ConfigRepo && change.repo != nil && change.Action == ConfigCreate:
+				_, err = CreateRepo(change.repo)
+			case change.Kind == ConfigRepo && change.repo != nil:
+				_, err = UpdateRepo(change.repo)
+			case change.Kind == ConfigPermission && change.permission != nil:
+				_, err = SavePermissionTarget(*change.permission)
+			default:
+				err = errors.New("Change for " + change.Kind + " '" + change.Key + "' wasn't made by PlanRepoConfig.")
+			}
+		default:
+			err = errors.New("Unknown action '" + change.Action + "' for " + change.Kind + " '" + change.Key + "'.")
+		}
+
+		if err != nil {
+			change.Status, change.Err = "Failed", err
+			failed++
+			strErr := fmt.Sprintf("%v\n", err)
+			common.LogTxtHandler().Error("FAILED: " + change.Action + " " + change.Kind + " '" + change.Key + "' - " + strErr)
+			continue
+		}
+		change.Status = "Success"
+	}
+
+	if failed != 0 {
+		err := errors.New(strconv.Itoa(failed) + " configuration change(s) failed.")
+		return results, err
+	}
+	return results, nil
+}
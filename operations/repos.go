@@ -0,0 +1,397 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// Repo types ('rclass') as used by Artifactory
+const (
+	RepoLocal	= "local"
+	RepoRemote	= "remote"
+	RepoVirtual	= "virtual"
+)
+
+// RepoConfig is implemented by LocalRepo, RemoteRepo, and VirtualRepo
+type RepoConfig interface {
+	GetKey() string
+	RepoType() string
+}
+
+// RepoBase holds the settings common to every repo type
+// Flags are pointers so that ones left unset (nil) aren't sent, and so aren't changed by UpdateRepo
+type RepoBase struct {
+	Key				string		`json:"key"`
+	PackageType		string		`json:"packageType,omitempty"`		// Ex: 'generic', 'maven', 'docker'
+	Description		string		`json:"description,omitempty"`
+	Notes			string		`json:"notes,omitempty"`
+	IncludesPattern	string		`json:"includesPattern,omitempty"`	// Ex: '**/*'
+	ExcludesPattern	string		`json:"excludesPattern,omitempty"`
+	RepoLayoutRef	string		`json:"repoLayoutRef,omitempty"`	// Ex: 'simple-default', 'maven-2-default'
+	Environments	[]string	`json:"environments,omitempty"`
+	XrayIndex		*bool		`json:"xrayIndex,omitempty"`
+}
+
+type LocalRepo struct {
+	RepoBase
+	HandleReleases			*bool		`json:"handleReleases,omitempty"`		// Defaults to true when not set
+	HandleSnapshots			*bool		`json:"handleSnapshots,omitempty"`		// Defaults to true when not set
+	MaxUniqueSnapshots		int			`json:"maxUniqueSnapshots,omitempty"`
+	PropertySets			[]string	`json:"propertySets,omitempty"`
+	ArchiveBrowsingEnabled	*bool		`json:"archiveBrowsingEnabled,omitempty"`
+	BlackedOut				*bool		`json:"blackedOut,omitempty"`
+	CdnRedirect				*bool		`json:"cdnRedirect,omitempty"`
+	DownloadRedirect		*bool		`json:"downloadRedirect,omitempty"`
+	PriorityResolution		*bool		`json:"priorityResolution,omitempty"`
+}
+
+type RemoteRepo struct {
+	RepoBase
+	Url							string			`json:"url"`
+	Username					string			`json:"username,omitempty"`
+	Password					string			`json:"password,omitempty"`					// Returned encrypted (or not at all) by GetRepo
+	Offline						*bool			`json:"offline,omitempty"`
+	BlackedOut					*bool			`json:"blackedOut,omitempty"`
+	StoreArtifactsLocally		*bool			`json:"storeArtifactsLocally,omitempty"`	// Defaults to true when not set
+	SynchronizeProperties		*bool			`json:"synchronizeProperties,omitempty"`	// Sync properties with a smart remote (replicated) repo
+	ShareConfiguration			*bool			`json:"shareConfiguration,omitempty"`
+	ListRemoteFolderItems		*bool			`json:"listRemoteFolderItems,omitempty"`
+	BlockMismatchingMimeTypes	*bool			`json:"blockMismatchingMimeTypes,omitempty"`
+	ContentSynchronisation		*ContentSync	`json:"contentSynchronisation,omitempty"`	// Smart remote repo settings
+	RetrievalCachePeriodSecs	int				`json:"retrievalCachePeriodSecs,omitempty"`
+}
+
+// ContentSync holds the smart remote repo settings, which keep a remote repo in sync with the Artifactory instance it points to
+type ContentSync struct {
+	Enabled		bool		`json:"enabled"`
+	Statistics	SyncToggle	`json:"statistics"`		// Report download statistics back to the source
+	Properties	SyncToggle	`json:"properties"`		// Sync properties with the source
+	Source		SyncSource	`json:"source"`
+}
+
+type SyncToggle struct {
+	Enabled	bool	`json:"enabled"`
+}
+
+type SyncSource struct {
+	OriginAbsenceDetection	bool	`json:"originAbsenceDetection"`	// Mark cached artifacts deleted from the source
+}
+
+type VirtualRepo struct {
+	RepoBase
+	Repositories									[]string	`json:"repositories"`
+	DefaultDeploymentRepo							string		`json:"defaultDeploymentRepo,omitempty"`
+	ArtifactoryRequestsCanRetrieveRemoteArtifacts	*bool		`json:"artifactoryRequestsCanRetrieveRemoteArtifacts,omitempty"`
+}
+
+// RepoSummary is a single entry from the repo list; see GetRepo for the full configuration
+type RepoSummary struct {
+	Key			string	`json:"key"`
+	Type		string	`json:"type"`			// 'LOCAL', 'REMOTE', 'VIRTUAL', or 'FEDERATED'
+	PackageType	string	`json:"packageType"`
+	Description	string	`json:"description"`
+	Url			string	`json:"url"`
+}
+
+// RepoFilter limits a repo list to a repo type ('local', 'remote', 'virtual', 'federated') and/or package type ('generic', 'maven', etc.)
+type RepoFilter struct {
+	Type		string
+	PackageType	string
+}
+
+func (r LocalRepo) GetKey() string     { return r.Key }
+func (r LocalRepo) RepoType() string   { return RepoLocal }
+func (r RemoteRepo) GetKey() string    { return r.Key }
+func (r RemoteRepo) RepoType() string  { return RepoRemote }
+func (r VirtualRepo) GetKey() string   { return r.Key }
+func (r VirtualRepo) RepoType() string { return RepoVirtual }
+
+func BoolPtr(value bool) *bool {
+	// Returns a pointer to the value, for setting the optional repo flags (ex: XrayIndex: operations.BoolPtr(true))
+	return &value
+}
+
+func marshalRepoConfig(config RepoConfig) ([]byte, error) {
+	// Adds the 'rclass' field Artifactory uses to tell the repo types apart
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	fields["rclass"] = config.RepoType()
+	return json.Marshal(fields)
+}
+
+func checkRepoConfig(config RepoConfig) error {
+	if config == nil || config.GetKey() == "" {
+		err := errors.New("Repo key can't be empty.")
+		common.LogTxtHandler().Error("Repo key can't be empty.")
+		return err
+	}
+	if strings.ContainsAny(config.GetKey(), "/\\:|?*\"<> ") {
+		err := errors.New("Repo key '" + config.GetKey() + "' contains invalid characters.")
+		common.LogTxtHandler().Error("Repo key '" + config.GetKey() + "' contains invalid characters.")
+		return err
+	}
+	switch c := config.(type) {
+	case RemoteRepo:
+		if c.Url == "" {
+			err := errors.New("Remote repo '" + c.Key + "' requires a URL.")
+			common.LogTxtHandler().Error("Remote repo '" + c.Key + "' requires a URL.")
+			return err
+		}
+	case *RemoteRepo:
+		return checkRepoConfig(*c)
+	}
+	return nil
+}
+
+func sendRepoConfig(method string, config RepoConfig) (string, error) {
+	err := checkRepoConfig(config)
+	if err != nil {
+		return "", err
+	}
+	data, err := marshalRepoConfig(config)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Unable to form repo configuration - " + strErr)
+		return "", err
+	}
+
+	requestPath := util.ServerApi + "/repositories/" + config.GetKey()
+	body, status, err := common.SendRequest(method, requestPath, strings.NewReader(string(data)), "application/json")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK && status != http.StatusCreated {
+		err := errors.New("Unable to save repo '" + config.GetKey() + "' (status code " + fmt.Sprint(status) + "): " + string(body))
+		common.LogTxtHandler().Error("Unable to save repo '" + config.GetKey() + "' (status code " + fmt.Sprint(status) + ")")
+		return fmt.Sprint(status), err
+	}
+	return "200", nil
+}
+
+func CreateRepo(config RepoConfig) (string, error) {
+	// Creates a local, remote, or virtual repo from its typed configuration; returns "200" if successful
+	common.LogTxtHandler().Info(">>> Creating " + configTypeName(config) + " repo: " + configKey(config) + "...")
+	statusCode, err := sendRepoConfig("PUT", config)
+	if err == nil {
+		common.LogTxtHandler().Info("Repo: " + config.GetKey() + " created.")
+	}
+	return statusCode, err
+}
+
+func UpdateRepo(config RepoConfig) (string, error) {
+	// Updates an existing repo with the given configuration; returns "200" if successful
+	// Fields left empty (strings, lists, and flags that aren't set) are left unchanged
+	// The repo type and package type can't be changed
+	common.LogTxtHandler().Info(">>> Updating " + configTypeName(config) + " repo: " + configKey(config) + "...")
+	statusCode, err := sendRepoConfig("POST", config)
+	if err == nil {
+		common.LogTxtHandler().Info("Repo: " + config.GetKey() + " updated.")
+	}
+	return statusCode, err
+}
+
+func configKey(config RepoConfig) string {
+	if config == nil {
+		return ""
+	}
+	return config.GetKey()
+}
+
+func configTypeName(config RepoConfig) string {
+	if config == nil {
+		return ""
+	}
+	return config.RepoType()
+}
+
+func GetRepo(repoKey string) (RepoConfig, error) {
+	// Gets the full configuration of a repo; the result is a LocalRepo, RemoteRepo, or VirtualRepo
+	// Ex: if local, ok := config.(operations.LocalRepo); ok { ... }
+	common.LogTxtHandler().Info(">>> Getting configuration for repo: " + repoKey + "...")
+
+	if repoKey == "" {
+		err := errors.New("Repo key can't be empty.")
+		common.LogTxtHandler().Error("Repo key can't be empty.")
+		return nil, err
+	}
+
+	body, status, err := common.SendRequest("GET", util.ServerApi+"/repositories/"+repoKey, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusBadRequest || status == http.StatusNotFound {
+		err := errors.New("Repo '" + repoKey + "' not found.")
+		common.LogTxtHandler().Error("Repo '" + repoKey + "' not found.")
+		return nil, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Unable to get repo '" + repoKey + "' (status code " + fmt.Sprint(status) + ").")
+		common.LogTxtHandler().Error("Unable to get repo '" + repoKey + "' (status code " + fmt.Sprint(status) + ").")
+		return nil, err
+	}
+
+	var class struct {
+		Rclass	string	`json:"rclass"`
+	}
+	err = json.Unmarshal(body, &class)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, err
+	}
+
+	var config RepoConfig
+	switch class.Rclass {
+	case RepoLocal:
+		var repo LocalRepo
+		err = json.Unmarshal(body, &repo)
+		config = repo
+	case RepoRemote:
+		var repo RemoteRepo
+		err = json.Unmarshal(body, &repo)
+		config = repo
+	case RepoVirtual:
+		var repo VirtualRepo
+		err = json.Unmarshal(body, &repo)
+		config = repo
+	default:
+		err = errors.New("Unsupported repo type '" + class.Rclass + "' for repo: " + repoKey)
+	}
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Unable to read repo configuration - " + strErr)
+		return nil, err
+	}
+	return config, nil
+}
+
+func DeleteRepo(repoKey string) (string, error) {
+	// Deletes a repo AND EVERYTHING IN IT; returns "200" if successful
+	common.LogTxtHandler().Info(">>> Deleting repo: " + repoKey + "...")
+
+	if repoKey == "" {
+		err := errors.New("Repo key can't be empty.")
+		common.LogTxtHandler().Error("Repo key can't be empty.")
+		return "", err
+	}
+
+	body, status, err := common.SendRequest("DELETE", util.ServerApi+"/repositories/"+repoKey, nil, "")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Unable to delete repo '" + repoKey + "' (status code " + fmt.Sprint(status) + "): " + string(body))
+		common.LogTxtHandler().Error("Unable to delete repo '" + repoKey + "' (status code " + fmt.Sprint(status) + ")")
+		return fmt.Sprint(status), err
+	}
+	common.LogTxtHandler().Info("Repo: " + repoKey + " deleted.")
+	return "200", nil
+}
+
+func ListRepoSummaries(filter ...RepoFilter) ([]RepoSummary, error) {
+	// Lists the repos the Identity Token has access to, optionally filtered by repo type and/or package type
+	common.LogTxtHandler().Info(">>> Getting list of available repos...")
+
+	params := url.Values{}
+	if len(filter) != 0 {
+		if filter[0].Type != "" {
+			params.Set("type", strings.ToLower(filter[0].Type))
+		}
+		if filter[0].PackageType != "" {
+			params.Set("packageType", strings.ToLower(filter[0].PackageType))
+		}
+	}
+	requestPath := util.ServerApi + "/repositories"
+	if len(params) != 0 {
+		requestPath = requestPath + "?" + params.Encode()
+	}
+
+	body, status, err := common.SendRequest("GET", requestPath, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Unable to list repos (status code " + fmt.Sprint(status) + ").")
+		common.LogTxtHandler().Error("Unable to list repos (status code " + fmt.Sprint(status) + ").")
+		return nil, err
+	}
+
+	// JSON return is an array of repos '[{"key":"repo_name1, "type":"LOCAL"...}, {"key":"repo_name2"}...]'
+	var repos []RepoSummary
+	err = json.Unmarshal(body, &repos)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, err
+	}
+	for _, r := range repos {
+		common.LogTxtHandler().Debug("FOUND REPO: " + r.Key + " (" + r.Type + ", " + r.PackageType + ")")
+	}
+	return repos, nil
+}
+
+func ListRepoConfigs(filter ...RepoFilter) ([]RepoConfig, error) {
+	// Same as ListRepoSummaries, but returns each repo's full configuration
+	// Configurations are fetched with at most util.MaxWorkers requests in flight; federated repos are left out
+	repos, err := ListRepoSummaries(filter...)
+	if err != nil {
+		return nil, err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	configs := make([]RepoConfig, len(repos))
+	sem := make(chan struct{}, max(util.MaxWorkers, 1))
+
+	for idx, r := range repos {
+		if strings.EqualFold(r.Type, "federated") {
+			common.LogTxtHandler().Debug("Skipping federated repo: " + r.Key)
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int, repoKey string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			config, err := GetRepo(repoKey)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			configs[idx] = config
+		}(idx, r.Key)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	var results []RepoConfig
+	for _, config := range configs {
+		if config != nil {
+			results = append(results, config)
+		}
+	}
+	return results, nil
+}
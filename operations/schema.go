@@ -0,0 +1,311 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// PropRule describes what's allowed for a single property key
+// Allowed lists the exact values permitted, Pattern is a regex the whole value must match, and
+// Type is one of "string" (default), "int", "bool", or "date" (YYYY-MM-DD or RFC3339)
+type PropRule struct {
+	Required		bool		`json:"required"`
+	Allowed			[]string	`json:"allowed"`
+	Pattern			string		`json:"pattern"`
+	Type			string		`json:"type"`
+}
+
+// PropSchema defines the properties expected on artifacts under a repo or path prefix (ex: '/images-local/windows')
+// FileTypes limits the schema to certain file extensions (ex: '.ova', '.vmtx'); empty applies it to every file
+// Unless AllowUnknown is true, property keys not listed in Props are rejected, which catches typos like 'relase'
+type PropSchema struct {
+	PathPrefix		string				`json:"pathPrefix"`
+	FileTypes		[]string			`json:"fileTypes"`
+	AllowUnknown	bool				`json:"allowUnknown"`
+	Props			map[string]PropRule	`json:"props"`
+	patterns		map[string]*regexp.Regexp
+}
+
+// SchemaViolation lists the problems found with one artifact's properties during an audit
+type SchemaViolation struct {
+	Path			string
+	Problems		[]string
+}
+
+// ErrPropSchema is wrapped by errors returned when properties don't conform to the registered schema
+var ErrPropSchema = errors.New("Properties do not conform to schema")
+
+var propSchemas []PropSchema
+var propSchemasMu sync.RWMutex
+
+func RegisterPropSchema(schema PropSchema) error {
+	// Adds a schema that SetArtifactProps, the upload tasks, and AuditPropSchema validate against
+	// The schema is copied, so changing it afterwards has no effect; register it again instead
+	// Registering a schema for a path prefix that already has one replaces it
+	if schema.PathPrefix == "" {
+		return errors.New("Property schema requires a repo or path prefix.")
+	}
+	schema.PathPrefix = "/" + strings.Trim(schema.PathPrefix, "/")
+	schema.patterns = make(map[string]*regexp.Regexp)
+	// The registered copy mustn't share the caller's slice and map, which are normalized below or may be reused
+	schema.FileTypes = slices.Clone(schema.FileTypes)
+	schema.Props = maps.Clone(schema.Props)
+
+	for key, rule := range schema.Props {
+		switch rule.Type {
+		case "", "string", "int", "bool", "date":
+		default:
+			return errors.New("Unsupported type '" + rule.Type + "' for property '" + key + "'. Supported types are 'string', 'int', 'bool', and 'date'.")
+		}
+		if rule.Pattern != "" {
+			pattern, err := regexp.Compile("^(?:" + rule.Pattern + ")$")
+			if err != nil {
+				return fmt.Errorf("Invalid pattern for property '%s': %w", key, err)
+			}
+			schema.patterns[key] = pattern
+		}
+	}
+	for idx, ext := range schema.FileTypes {
+		if !strings.HasPrefix(ext, ".") {
+			schema.FileTypes[idx] = "." + ext
+		}
+	}
+
+	propSchemasMu.Lock()
+	defer propSchemasMu.Unlock()
+	for idx := range propSchemas {
+		if propSchemas[idx].PathPrefix == schema.PathPrefix {
+			propSchemas[idx] = schema
+			return nil
+		}
+	}
+	propSchemas = append(propSchemas, schema)
+	common.LogTxtHandler().Debug("Registered property schema for: " + schema.PathPrefix)
+	return nil
+}
+
+func LoadPropSchemas(filePath string) error {
+	// Reads a JSON file containing a list of schemas and registers each of them
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error reading schema file. " + strErr)
+		return err
+	}
+	var schemas []PropSchema
+	err = json.Unmarshal(data, &schemas)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal schema file - " + strErr)
+		return err
+	}
+	for _, schema := range schemas {
+		err = RegisterPropSchema(schema)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ClearPropSchemas() {
+	propSchemasMu.Lock()
+	defer propSchemasMu.Unlock()
+	propSchemas = nil
+}
+
+func artifactPathFromUri(artifUri string) string {
+	// '/repo-key/folder/artifact.ext' from either an artifact URI or an Artifactory path
+	artifactPath := strings.TrimPrefix(artifUri, util.ServerApi + "/storage")
+	return "/" + strings.Trim(artifactPath, "/")
+}
+
+func GetPropSchemaForPath(artifPath string) (PropSchema, bool) {
+	// Returns the schema with the longest path prefix that covers the artifact, if any
+	// Takes an artifact URI or an Artifactory path ('/repo-key/folder/artifact.ext')
+	artifPath = artifactPathFromUri(artifPath)
+	var match PropSchema
+	found := false
+
+	propSchemasMu.RLock()
+	defer propSchemasMu.RUnlock()
+	for _, schema := range propSchemas {
+		if artifPath != schema.PathPrefix && !strings.HasPrefix(artifPath, schema.PathPrefix + "/") {
+			continue
+		}
+		if len(schema.FileTypes) != 0 && path.Ext(artifPath) != "" {
+			matchesType := false
+			for _, ext := range schema.FileTypes {
+				if strings.EqualFold(path.Ext(artifPath), ext) {
+					matchesType = true
+				}
+			}
+			if !matchesType {
+				continue
+			}
+		}
+		if !found || len(schema.PathPrefix) > len(match.PathPrefix) {
+			match = schema
+			found = true
+		}
+	}
+	return match, found
+}
+
+func checkPropValue(schema PropSchema, key, value string) string {
+	// Returns a description of the problem with the value, or empty if it's fine
+	rule, found := schema.Props[key]
+	if !found {
+		if schema.AllowUnknown {
+			return ""
+		}
+		return "property '" + key + "' is not defined in the schema"
+	}
+
+	switch rule.Type {
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return "property '" + key + "' must be an integer, got '" + value + "'"
+		}
+	case "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "property '" + key + "' must be true or false, got '" + value + "'"
+		}
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			_, err = time.Parse(time.RFC3339, value)
+		}
+		if err != nil {
+			return "property '" + key + "' must be a date (YYYY-MM-DD or RFC3339), got '" + value + "'"
+		}
+	}
+
+	if len(rule.Allowed) != 0 {
+		allowed := false
+		for _, a := range rule.Allowed {
+			if a == value {
+				allowed = true
+			}
+		}
+		if !allowed {
+			return "property '" + key + "' must be one of [" + strings.Join(rule.Allowed, ", ") + "], got '" + value + "'"
+		}
+	}
+	if pattern, ok := schema.patterns[key]; ok && !pattern.MatchString(value) {
+		return "property '" + key + "' must match '" + rule.Pattern + "', got '" + value + "'"
+	}
+	return ""
+}
+
+func checkPropsAgainstSchema(schema PropSchema, props map[string][]string, complete bool) []string {
+	// When complete is true, the properties are the artifact's full set, so missing required keys are reported too
+	var problems []string
+	for key, values := range props {
+		for _, v := range values {
+			if problem := checkPropValue(schema, key, v); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+	}
+	if complete {
+		for key, rule := range schema.Props {
+			if _, found := props[key]; rule.Required && !found {
+				problems = append(problems, "required property '" + key + "' is missing")
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+func ValidateProps(artifPath string, listKvProps []string, complete bool) error {
+	// Validates property key/values ('key=value') against the schema covering the artifact, if there is one
+	// Set complete to true when the list is every property the artifact will have, to also check required keys;
+	// leave it false for partial updates, where other properties may already be set on the artifact
+	schema, found := GetPropSchemaForPath(artifPath)
+	if !found {
+		return nil
+	}
+
+	props := make(map[string][]string)
+	for _, kv := range listKvProps {
+		key, value, _ := strings.Cut(kv, "=")
+		props[key] = append(props[key], value)
+	}
+
+	problems := checkPropsAgainstSchema(schema, props, complete)
+	if len(problems) != 0 {
+		for _, p := range problems {
+			common.LogTxtHandler().Error("Schema violation for " + artifactPathFromUri(artifPath) + ": " + p)
+		}
+		return fmt.Errorf("%w (%s): %s", ErrPropSchema, schema.PathPrefix, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func AuditPropSchema(itemPath string) ([]SchemaViolation, error) {
+	// Checks the properties of every file under a repo or folder path ('/repo-key/folder') against the registered
+	// schemas and lists the artifacts that don't conform, including any missing required properties
+	common.LogTxtHandler().Info(">>> Auditing Properties Under Path: " + itemPath + "...")
+	var violations []SchemaViolation
+
+	if itemPath == "" {
+		err := errors.New("Unable to audit properties without a repo or folder path.")
+		common.LogTxtHandler().Error("Unable to audit properties without a repo or folder path.")
+		return nil, err
+	}
+
+	records, err := getPropRecordsByAql(itemPath)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Warn("AQL lookup unavailable; falling back to storage listing - " + strErr)
+
+		listArtifUris, err := listFilesUnderPath(itemPath)
+		if err != nil {
+			return nil, err
+		}
+		records = nil
+		if len(listArtifUris) != 0 {
+			details, err := GetArtifactDetailsForList(listArtifUris)
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range details {
+				records = append(records, PropRecord{Path: artifactPathFromUri(d.Uri), Properties: d.Properties})
+			}
+		}
+	}
+
+	for _, r := range records {
+		schema, found := GetPropSchemaForPath(r.Path)
+		if !found {
+			continue
+		}
+		problems := checkPropsAgainstSchema(schema, r.Properties, true)
+		if len(problems) != 0 {
+			violations = append(violations, SchemaViolation{Path: artifactPathFromUri(r.Path), Problems: problems})
+			common.LogTxtHandler().Warn("NON-CONFORMING ARTIFACT: " + artifactPathFromUri(r.Path) + " - " + strings.Join(problems, "; "))
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+
+	common.LogTxtHandler().Info("Found " + fmt.Sprint(len(violations)) + " non-conforming artifacts.")
+	return violations, nil
+}
@@ -0,0 +1,160 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// ArtifactStats is how often, when, and by whom an artifact was downloaded
+// Remote counts are downloads through a remote repo on another (smart remote) Artifactory instance
+// Dates are the zero time if the artifact was never downloaded; Err is set if the stats couldn't be gotten (GetStatsForList)
+type ArtifactStats struct {
+	Uri						string
+	DownloadCount			int64
+	LastDownloaded			time.Time
+	LastDownloadedBy		string
+	RemoteDownloadCount		int64
+	RemoteLastDownloaded	time.Time
+	RemoteLastDownloadedBy	string
+	Err						error
+}
+
+func (s ArtifactStats) Downloaded() bool {
+	// Whether the artifact was ever downloaded, locally or remotely
+	return s.DownloadCount > 0 || s.RemoteDownloadCount > 0
+}
+
+func toArtifUri(itemPath string) string {
+	// Accepts either an artifact URI or an Artifactory path ('/repo-key/folder/artifact.ext')
+	if strings.HasPrefix(itemPath, "http://") || strings.HasPrefix(itemPath, "https://") {
+		return itemPath
+	}
+	return util.ServerApi + "/storage/" + strings.Trim(itemPath, "/")
+}
+
+func epochMillisToTime(millis int64) time.Time {
+	if millis <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis).UTC()
+}
+
+func GetStats(artifPath string) (ArtifactStats, error) {
+	// Gets the download statistics of an artifact by its artifact URI or Artifactory path ('/repo-key/folder/artifact.ext')
+	artifUri := toArtifUri(artifPath)
+	stats := ArtifactStats{Uri: artifUri}
+	common.LogTxtHandler().Info(">>> Getting download stats for: " + artifUri + "...")
+
+	if strings.Trim(artifPath, "/") == "" {
+		err := errors.New("Unable to get download stats without an artifact URI or path.")
+		common.LogTxtHandler().Error("Unable to get download stats without an artifact URI or path.")
+		return stats, err
+	}
+
+	body, status, err := common.SendRequestNoCache("GET", artifUri + "?stats", nil, "")
+	if err != nil {
+		return stats, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Unable to get download stats for: " + artifUri + " (status code " + strconv.Itoa(status) + ")")
+		common.LogTxtHandler().Error("Unable to get download stats for: " + artifUri + " (status code " + strconv.Itoa(status) + ")")
+		return stats, err
+	}
+
+	var jsonData struct {
+		DownloadCount			int64	`json:"downloadCount"`
+		LastDownloaded			int64	`json:"lastDownloaded"`
+		LastDownloadedBy		string	`json:"lastDownloadedBy"`
+		RemoteDownloadCount		int64	`json:"remoteDownloadCount"`
+		RemoteLastDownloaded	int64	`json:"remoteLastDownloaded"`
+		RemoteLastDownloadedBy	string	`json:"remoteLastDownloadedBy"`
+	}
+	err = json.Unmarshal(body, &jsonData)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return stats, err
+	}
+
+	stats.DownloadCount = jsonData.DownloadCount
+	stats.LastDownloaded = epochMillisToTime(jsonData.LastDownloaded)
+	stats.LastDownloadedBy = jsonData.LastDownloadedBy
+	stats.RemoteDownloadCount = jsonData.RemoteDownloadCount
+	stats.RemoteLastDownloaded = epochMillisToTime(jsonData.RemoteLastDownloaded)
+	stats.RemoteLastDownloadedBy = jsonData.RemoteLastDownloadedBy
+	return stats, nil
+}
+
+func GetStatsForList(listArtifUris []string) ([]ArtifactStats, error) {
+	// Gets the download statistics of each artifact in the list (ex: from a search), in the same order
+	// Requests are made with at most util.MaxWorkers in flight; an artifact whose stats couldn't be gotten has its Err set,
+	// and the rest are still returned, along with an error if any failed
+	common.LogTxtHandler().Info(">>> Getting download stats for list of artifacts...")
+
+	if len(listArtifUris) == 0 {
+		err := errors.New("List of artifacts cannot be empty.")
+		common.LogTxtHandler().Error("List of artifacts cannot be empty.")
+		return nil, err
+	}
+
+	results := make([]ArtifactStats, len(listArtifUris))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := 0
+	sem := make(chan struct{}, max(util.MaxWorkers, 1))
+
+	for idx := range listArtifUris {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			stats, err := GetStats(listArtifUris[idx])
+			stats.Err = err
+			results[idx] = stats
+			if err != nil {
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+		}(idx)
+	}
+	wg.Wait()
+	if failed != 0 {
+		err := errors.New("Unable to get download stats for " + strconv.Itoa(failed) + " of " + strconv.Itoa(len(results)) + " artifacts.")
+		common.LogTxtHandler().Error("Unable to get download stats for " + strconv.Itoa(failed) + " of " + strconv.Itoa(len(results)) + " artifacts.")
+		return results, err
+	}
+	return results, nil
+}
+
+func GetStatsUnderPath(folderPath string, opts WalkOptions) ([]ArtifactStats, error) {
+	// Gets the download statistics of every file under a repo or folder ('/repo-key/folder')
+	// The files are found with WalkTree, so its options (depth limit, include/exclude globs) apply; only their paths are needed
+	var listArtifUris []string
+	opts.SkipFileInfo = true
+	err := WalkTree(folderPath, opts, func(entry WalkEntry) error {
+		if !entry.Folder {
+			listArtifUris = append(listArtifUris, toArtifUri(entry.Path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(listArtifUris) == 0 {
+		common.LogTxtHandler().Info("No files found under: " + folderPath)
+		return nil, nil
+	}
+	return GetStatsForList(listArtifUris)
+}
@@ -0,0 +1,220 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// StorageUsage is the space used by a folder or repo, and how many files and folders it holds
+// Path is '/repo-key/folder' for folders and '/repo-key' for repos; RepoType and PackageType are only set for repos
+// LastModified is the newest last modified date of the files under a folder; it isn't set for repos
+type StorageUsage struct {
+	Path			string		`json:"path"`
+	RepoType		string		`json:"repoType,omitempty"`
+	PackageType		string		`json:"packageType,omitempty"`
+	Size			int64		`json:"size"`
+	FileCount		int64		`json:"fileCount"`
+	FolderCount		int64		`json:"folderCount"`
+	LastModified	time.Time	`json:"lastModified"`
+}
+
+// StorageInfo is the instance-wide storage summary from /api/storageinfo
+// The binaries and file store values are human readable, as returned by Artifactory (ex: '3.48 GB', '32.22 GB (15.77%)')
+type StorageInfo struct {
+	BinariesCount		string
+	BinariesSize		string
+	ArtifactsSize		string
+	Optimization		string
+	StorageType			string
+	TotalSpace			string
+	UsedSpace			string
+	FreeSpace			string
+	Repos				[]StorageUsage
+}
+
+func GetFolderStats(rootPath string, depth int) ([]StorageUsage, error) {
+	// Adds up the size, file count, and folder count of the root ('/repo-key/folder') and of each folder under it, down to 'depth'
+	// levels (0 for just the root; ex: 2 for '/images-dev/<os>/<image>'); each folder's totals include everything under it
+	// Walks the tree with WalkTree, so it uses the file list API when available
+	common.LogTxtHandler().Info(">>> Getting folder stats for: " + rootPath + "...")
+
+	root := "/" + strings.Trim(rootPath, "/")
+	stats := map[string]*StorageUsage{root: {Path: root}}
+	var order []string
+
+	err := WalkTree(root, WalkOptions{}, func(entry WalkEntry) error {
+		parent := entry.Path[:strings.LastIndex(entry.Path, "/")]
+		if entry.Folder && entry.Depth <= depth {
+			stats[entry.Path] = &StorageUsage{Path: entry.Path}
+			order = append(order, entry.Path)
+		}
+
+		// Counts the entry towards its parent and every reported folder above it
+		for folder := parent; len(folder) >= len(root); folder = folder[:strings.LastIndex(folder, "/")] {
+			s, found := stats[folder]
+			if found {
+				if entry.Folder {
+					s.FolderCount++
+				} else {
+					s.FileCount++
+					s.Size += entry.Size
+					if entry.LastModified.After(s.LastModified) {
+						s.LastModified = entry.LastModified
+					}
+				}
+			}
+			if folder == root {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := []StorageUsage{*stats[root]}
+	sort.Strings(order)
+	for _, folder := range order {
+		usage = append(usage, *stats[folder])
+	}
+	return usage, nil
+}
+
+func GetStorageInfo() (StorageInfo, error) {
+	// Gets the storage summary and per-repo usage from /api/storageinfo
+	// Artifactory recalculates this periodically, so it can be a little behind; see RefreshStorageInfo
+	common.LogTxtHandler().Info(">>> Getting storage info...")
+	var info StorageInfo
+
+	body, status, err := common.SendRequest("GET", util.ServerApi + "/storageinfo", nil, "")
+	if err != nil {
+		return info, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Unable to get storage info (status code " + strconv.Itoa(status) + "): " + string(body))
+		common.LogTxtHandler().Error("Unable to get storage info (status code " + strconv.Itoa(status) + ")")
+		return info, err
+	}
+
+	var jsonData struct {
+		BinariesSummary struct {
+			BinariesCount	string	`json:"binariesCount"`
+			BinariesSize	string	`json:"binariesSize"`
+			ArtifactsSize	string	`json:"artifactsSize"`
+			Optimization	string	`json:"optimization"`
+		}	`json:"binariesSummary"`
+		FileStoreSummary struct {
+			StorageType		string	`json:"storageType"`
+			TotalSpace		string	`json:"totalSpace"`
+			UsedSpace		string	`json:"usedSpace"`
+			FreeSpace		string	`json:"freeSpace"`
+		}	`json:"fileStoreSummary"`
+		RepositoriesSummaryList []struct {
+			RepoKey				string	`json:"repoKey"`
+			RepoType			string	`json:"repoType"`
+			PackageType			string	`json:"packageType"`
+			FoldersCount		int64	`json:"foldersCount"`
+			FilesCount			int64	`json:"filesCount"`
+			UsedSpace			string	`json:"usedSpace"`
+			UsedSpaceInBytes	*int64	`json:"usedSpaceInBytes"`
+		}	`json:"repositoriesSummaryList"`
+	}
+	err = json.Unmarshal(body, &jsonData)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return info, err
+	}
+
+	info = StorageInfo{
+		BinariesCount: jsonData.BinariesSummary.BinariesCount,
+		BinariesSize: jsonData.BinariesSummary.BinariesSize,
+		ArtifactsSize: jsonData.BinariesSummary.ArtifactsSize,
+		Optimization: jsonData.BinariesSummary.Optimization,
+		StorageType: jsonData.FileStoreSummary.StorageType,
+		TotalSpace: jsonData.FileStoreSummary.TotalSpace,
+		UsedSpace: jsonData.FileStoreSummary.UsedSpace,
+		FreeSpace: jsonData.FileStoreSummary.FreeSpace,
+	}
+	for _, r := range jsonData.RepositoriesSummaryList {
+		if r.RepoKey == "TOTAL" {
+			continue
+		}
+		// Older versions only report used space as a human readable value
+		var size int64
+		if r.UsedSpaceInBytes != nil {
+			size = *r.UsedSpaceInBytes
+		} else {
+			size, err = common.ParseSize(r.UsedSpace)
+			if err != nil {
+				common.LogTxtHandler().Warn("Unable to parse used space '" + r.UsedSpace + "' of repo: " + r.RepoKey)
+			}
+		}
+		info.Repos = append(info.Repos, StorageUsage{
+			Path: "/" + r.RepoKey,
+			RepoType: common.ConvertToLowercase(r.RepoType),
+			PackageType: r.PackageType,
+			Size: size,
+			FileCount: r.FilesCount,
+			FolderCount: r.FoldersCount,
+		})
+	}
+	return info, nil
+}
+
+func RefreshStorageInfo() error {
+	// Asks Artifactory to recalculate the storage info; this runs in the background, so results show up in GetStorageInfo later
+	common.LogTxtHandler().Info(">>> Refreshing storage info...")
+	body, status, err := common.SendRequest("POST", util.ServerApi + "/storageinfo/calculate", nil, "")
+	if err != nil {
+		return err
+	}
+	if status != http.StatusAccepted && status != http.StatusOK {
+		err := errors.New("Unable to refresh storage info (status code " + strconv.Itoa(status) + "): " + string(body))
+		common.LogTxtHandler().Error("Unable to refresh storage info (status code " + strconv.Itoa(status) + ")")
+		return err
+	}
+	return nil
+}
+
+func SortStorageUsage(usage []StorageUsage, largestFirst bool) {
+	// Sorts by size, then by path; largest first or smallest first
+	sort.SliceStable(usage, func(i, j int) bool {
+		if usage[i].Size != usage[j].Size {
+			return (usage[i].Size > usage[j].Size) == largestFirst
+		}
+		return usage[i].Path < usage[j].Path
+	})
+}
+
+func WriteStorageReport(w io.Writer, usage []StorageUsage, format string) error {
+	// Writes the usage as a human readable table ('text') or as JSON ('json'), in the order given (see SortStorageUsage)
+	format = common.ConvertToLowercase(format)
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(usage)
+	case "text", "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "PATH\tSIZE\tFILES\tFOLDERS")
+		for _, u := range usage {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", u.Path, common.FormatSize(u.Size), u.FileCount, u.FolderCount)
+		}
+		return tw.Flush()
+	default:
+		return errors.New("Unsupported report format '" + format + "'. Supported formats are 'text' and 'json'.")
+	}
+}
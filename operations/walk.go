@@ -0,0 +1,287 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// Returned by a WalkFunc to end the walk early (WalkTree then returns nil), or to skip a folder's contents
+var ErrStopWalk = errors.New("Walk stopped.")
+var ErrSkipFolder = errors.New("Skip folder.")
+
+// WalkEntry is a file or folder visited by WalkTree
+// Path is the Artifactory path ('/repo-key/folder/artifact.ext'); Depth is 1 for the direct children of the walk's root
+// Size and Sha1 are only set for files
+type WalkEntry struct {
+	Path			string
+	Name			string
+	Folder			bool
+	Depth			int
+	Size			int64
+	LastModified	time.Time
+	Sha1			string
+}
+
+// WalkOptions control how WalkTree traverses the tree
+// Include globs limit which files are visited (folders are still visited and descended); Exclude globs skip matching
+// files and folders along with everything under them. See common.GlobToRegexp for the glob syntax.
+type WalkOptions struct {
+	MaxDepth		int			// 0 for no limit
+	Include			[]string
+	Exclude			[]string
+	Workers			int			// Defaults to util.MaxWorkers
+	NoDeepList		bool		// Walks folder by folder even when the file list API ('?list&deep=1') is available
+	SkipFileInfo	bool		// When walking folder by folder, visits files without their Size, LastModified, and Sha1
+}
+
+// WalkFunc is called once for each file and folder; calls are never made at the same time, so it doesn't need to be thread safe
+// Returning ErrSkipFolder for a folder skips its contents; returning ErrStopWalk ends the walk; any other error ends the walk
+// and is returned by WalkTree
+type WalkFunc func(entry WalkEntry) error
+
+type treeWalker struct {
+	root		string
+	opts		WalkOptions
+	include		[]*regexp.Regexp
+	exclude		[]*regexp.Regexp
+	visit		WalkFunc
+
+	visitMu		sync.Mutex
+	errMu		sync.Mutex
+	err			error
+	stopped		bool
+	wg			sync.WaitGroup
+	sem			chan struct{}
+}
+
+func WalkTree(rootPath string, opts WalkOptions, visit WalkFunc) error {
+	// Walks a repo or a folder within it ('/repo-key/folder') and calls visit for each file and folder under it
+	// Uses a single request to the file list API when it's available (Artifactory Pro), otherwise walks folder by folder
+	// with at most opts.Workers (util.MaxWorkers by default) requests in flight
+	common.LogTxtHandler().Info(">>> Walking tree: " + rootPath + "...")
+
+	rootPath = strings.Trim(rootPath, "/")
+	if rootPath == "" {
+		err := errors.New("Unable to walk without a repo or folder path.")
+		common.LogTxtHandler().Error("Unable to walk without a repo or folder path.")
+		return err
+	}
+	if visit == nil {
+		err := errors.New("Unable to walk without a visit function.")
+		common.LogTxtHandler().Error("Unable to walk without a visit function.")
+		return err
+	}
+
+	w := &treeWalker{root: "/" + rootPath, opts: opts, visit: visit}
+	for _, pattern := range opts.Include {
+		w.include = append(w.include, common.GlobToRegexp(pattern))
+	}
+	for _, pattern := range opts.Exclude {
+		w.exclude = append(w.exclude, common.GlobToRegexp(pattern))
+	}
+
+	if !opts.NoDeepList {
+		walked, err := w.walkDeepList()
+		if err != nil || walked {
+			return err
+		}
+		common.LogTxtHandler().Debug("File list API unavailable; walking folder by folder...")
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = util.MaxWorkers
+	}
+	w.sem = make(chan struct{}, max(workers, 1))
+	w.walkFolder(w.root, 0)
+	w.wg.Wait()
+	return w.err
+}
+
+func (w *treeWalker) matches(patterns []*regexp.Regexp, itemPath string) bool {
+	for _, re := range patterns {
+		if re.MatchString(strings.TrimPrefix(itemPath, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *treeWalker) isStopped() bool {
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+	return w.stopped
+}
+
+func (w *treeWalker) stop(err error) {
+	// Records the first error (ErrStopWalk isn't one) and stops any further requests and visits
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+	if !w.stopped && err != ErrStopWalk {
+		w.err = err
+	}
+	w.stopped = true
+}
+
+func (w *treeWalker) visitEntry(entry WalkEntry) (descend bool) {
+	// Applies the include/exclude globs and depth limit, then calls the visit function; returns whether to walk a folder's contents
+	if w.matches(w.exclude, entry.Path) {
+		return false
+	}
+	if !entry.Folder && len(w.include) != 0 && !w.matches(w.include, entry.Path) {
+		return false
+	}
+
+	w.visitMu.Lock()
+	defer w.visitMu.Unlock()
+	if w.isStopped() {
+		return false
+	}
+	err := w.visit(entry)
+	if err == ErrSkipFolder {
+		return false
+	}
+	if err != nil {
+		w.stop(err)
+		return false
+	}
+	return entry.Folder && (w.opts.MaxDepth <= 0 || entry.Depth < w.opts.MaxDepth)
+}
+
+func (w *treeWalker) walkDeepList() (bool, error) {
+	// Gets the whole tree in one request; returns false if the file list API isn't available so the caller can fall back
+	requestPath := util.ServerApi + "/storage" + w.root + "?list&deep=1&listFolders=1"
+	if w.opts.MaxDepth > 0 {
+		requestPath = requestPath + "&depth=" + strconv.Itoa(w.opts.MaxDepth)
+	}
+	body, status, err := common.SendRequest("GET", requestPath, nil, "")
+	if err != nil {
+		return false, err
+	}
+	if status != http.StatusOK {
+		return false, nil
+	}
+
+	var jsonData struct {
+		Files []struct {
+			Uri				string	`json:"uri"`
+			Size			int64	`json:"size"`
+			LastModified	string	`json:"lastModified"`
+			Folder			bool	`json:"folder"`
+			Sha1			string	`json:"sha1"`
+		}	`json:"files"`
+	}
+	err = json.Unmarshal(body, &jsonData)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return true, err
+	}
+
+	// Sorted so each folder is visited before its contents
+	files := jsonData.Files
+	sort.Slice(files, func(i, j int) bool { return files[i].Uri < files[j].Uri })
+
+	var skipped []string
+	for _, f := range files {
+		if w.isStopped() {
+			break
+		}
+		entryPath := w.root + f.Uri
+		if isUnderFolder(skipped, entryPath) {
+			continue
+		}
+		depth := strings.Count(f.Uri, "/")
+		if w.opts.MaxDepth > 0 && depth > w.opts.MaxDepth {
+			continue
+		}
+		lastModified, _ := common.ParseArtifTime(f.LastModified)
+		entry := WalkEntry{Path: entryPath, Name: entryPath[strings.LastIndex(entryPath, "/")+1:], Folder: f.Folder, Depth: depth, LastModified: lastModified}
+		if !f.Folder {
+			entry.Size, entry.Sha1 = f.Size, f.Sha1
+		}
+		if !w.visitEntry(entry) && f.Folder {
+			skipped = append(skipped, entryPath)
+		}
+	}
+	return true, w.err
+}
+
+func isUnderFolder(folders []string, itemPath string) bool {
+	// Checks whether the item is under any of the folders
+	for _, folder := range folders {
+		if strings.HasPrefix(itemPath, folder + "/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *treeWalker) walkFolder(folderPath string, depth int) {
+	// Lists the folder's children, visiting each and walking sub-folders concurrently
+	// File metadata needs its own request per file, made only for files that pass the include/exclude globs (and not
+	// at all with opts.SkipFileInfo)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.sem <- struct{}{}
+		info, err := GetItemInfo(folderPath)
+		<-w.sem
+		if err != nil {
+			w.stop(err)
+			return
+		}
+
+		for _, child := range info.Children {
+			if w.isStopped() {
+				return
+			}
+			entry := WalkEntry{Path: folderPath + child.Child, Name: strings.TrimPrefix(child.Child, "/"), Folder: child.IsFolder, Depth: depth + 1}
+			if child.IsFolder {
+				if w.visitEntry(entry) {
+					w.walkFolder(entry.Path, entry.Depth)
+				}
+				continue
+			}
+			if w.opts.SkipFileInfo {
+				w.visitEntry(entry)
+				continue
+			}
+			if w.matches(w.exclude, entry.Path) || (len(w.include) != 0 && !w.matches(w.include, entry.Path)) {
+				continue
+			}
+			w.visitFile(entry)
+		}
+	}()
+}
+
+func (w *treeWalker) visitFile(entry WalkEntry) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.sem <- struct{}{}
+		if w.isStopped() {
+			<-w.sem
+			return
+		}
+		info, err := GetItemInfo(entry.Path)
+		<-w.sem
+		if err != nil {
+			w.stop(err)
+			return
+		}
+		entry.Size, entry.LastModified, entry.Sha1 = info.Size, info.LastModified, info.Checksums.Sha1
+		w.visitEntry(entry)
+	}()
+}
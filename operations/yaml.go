@@ -0,0 +1,519 @@
+package operations
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A minimal YAML reader for configuration files. It covers the subset these files need: block maps and lists,
+// flow ('[a, b]' and '{a: b}') collections, plain and quoted scalars, and comments.
+// Anchors, aliases, tags, multi-line strings, and multiple documents aren't supported and are reported as errors.
+// parseYaml returns map[string]any, []any, string (quoted scalars), yamlPlain (unquoted scalars), and nil; yamlToJson
+// then resolves each unquoted scalar against the type it's decoded into, so 'description: 2024' stays a string while
+// 'xrayIndex: true' becomes a bool.
+
+var yamlFloatRegexp = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+type yamlLine struct {
+	num		int			// Line number in the file, for errors
+	indent	int
+	text	string		// Without indentation or comments
+}
+
+// yamlPlain is an unquoted scalar, kept as written until the type it's decoded into is known
+type yamlPlain struct {
+	text	string
+	num		int			// Line number in the file, for errors
+}
+
+type yamlParser struct {
+	lines	[]yamlLine
+	pos		int
+}
+
+func parseYaml(data string) (any, error) {
+	// Parses a YAML document; an empty document is an empty map
+	lines, err := splitYamlLines(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return map[string]any{}, nil
+	}
+
+	p := &yamlParser{lines: lines}
+	value, err := p.parseBlock(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, yamlError(p.lines[p.pos].num, "unexpected '" + p.lines[p.pos].text + "'")
+	}
+	return value, nil
+}
+
+func yamlError(lineNum int, msg string) error {
+	return errors.New("YAML line " + strconv.Itoa(lineNum) + ": " + msg + ".")
+}
+
+func splitYamlLines(data string) ([]yamlLine, error) {
+	// Drops blank lines, comments, and document markers, and measures each line's indentation
+	var lines []yamlLine
+	for idx, raw := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		num := idx + 1
+		text := strings.TrimLeft(raw, " ")
+		indent := len(raw) - len(text)
+		if strings.HasPrefix(text, "\t") {
+			return nil, yamlError(num, "tabs can't be used for indentation")
+		}
+		text = strings.TrimSpace(stripYamlComment(text))
+		if text == "" || (indent == 0 && (text == "---" || text == "...")) {
+			continue
+		}
+		if indent == 0 && strings.HasPrefix(text, "%") {
+			return nil, yamlError(num, "directives aren't supported")
+		}
+		lines = append(lines, yamlLine{num: num, indent: indent, text: text})
+	}
+	return lines, nil
+}
+
+func stripYamlComment(text string) string {
+	// A '#' starts a comment at the start of the line or after a space, unless it's inside a quoted string
+	// Quotes only count at the start of a scalar, so apostrophes in plain text (ex: team's) don't hide comments
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.ContainsRune(" \t:[{,-", rune(text[i-1]))):
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+func isYamlListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	// Parses the map, list, or scalar starting at the current line
+	line := p.lines[p.pos]
+	if isYamlListItem(line.text) {
+		return p.parseList(indent)
+	}
+	if _, _, isKey, err := splitYamlKey(line); err != nil {
+		return nil, err
+	} else if isKey {
+		return p.parseMap(indent)
+	}
+	p.pos++
+	return p.parseValue(line)
+}
+
+func (p *yamlParser) parseList(indent int) (any, error) {
+	list := []any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYamlListItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		content := strings.TrimSpace(line.text[1:])
+		if content == "" {
+			p.pos++
+			var item any
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				var err error
+				if item, err = p.parseBlock(p.lines[p.pos].indent); err != nil {
+					return nil, err
+				}
+			}
+			list = append(list, item)
+			continue
+		}
+
+		// The item's content is parsed as if it started its own line; lines that follow at the same column
+		// belong to it (ex: the other keys of a map in a list)
+		p.lines[p.pos] = yamlLine{num: line.num, indent: indent + len(line.text) - len(content), text: content}
+		item, err := p.parseBlock(p.lines[p.pos].indent)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+		return nil, yamlError(p.lines[p.pos].num, "unexpected indentation")
+	}
+	return list, nil
+}
+
+func (p *yamlParser) parseMap(indent int) (any, error) {
+	fields := map[string]any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && !isYamlListItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		key, rest, isKey, err := splitYamlKey(line)
+		if err != nil {
+			return nil, err
+		}
+		if !isKey {
+			return nil, yamlError(line.num, "expected 'key: value'")
+		}
+		if _, exists := fields[key]; exists {
+			return nil, yamlError(line.num, "key '" + key + "' is defined more than once")
+		}
+
+		var value any
+		if rest != "" {
+			p.pos++
+			value, err = p.parseValue(yamlLine{num: line.num, indent: line.indent, text: rest})
+		} else {
+			// The value is the indented block below, or a list at the same indentation (ex: 'local:' then '- key: ...')
+			p.pos++
+			if p.pos < len(p.lines) && (p.lines[p.pos].indent > indent || (p.lines[p.pos].indent == indent && isYamlListItem(p.lines[p.pos].text))) {
+				value, err = p.parseBlock(p.lines[p.pos].indent)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = value
+	}
+	if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+		return nil, yamlError(p.lines[p.pos].num, "unexpected indentation")
+	}
+	return fields, nil
+}
+
+func splitYamlKey(line yamlLine) (string, string, bool, error) {
+	// Splits 'key: value' into its key and the (possibly empty) value; the bool is false if the line isn't a key
+	text := line.text
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return "", "", false, nil
+	}
+	if strings.HasPrefix(text, "? ") || strings.HasPrefix(text, "&") || strings.HasPrefix(text, "*") || strings.HasPrefix(text, "!") {
+		return "", "", false, yamlError(line.num, "complex keys, anchors, aliases, and tags aren't supported")
+	}
+
+	var key string
+	var after string
+	if text[0] == '"' || text[0] == '\'' {
+		quoted, rest, err := readYamlQuoted(text, line.num)
+		if err != nil {
+			return "", "", false, err
+		}
+		rest = strings.TrimLeft(rest, " ")
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false, nil
+		}
+		key, after = quoted, rest[1:]
+	} else {
+		idx := strings.Index(text, ": ")
+		if idx < 0 && strings.HasSuffix(text, ":") {
+			idx = len(text) - 1
+		}
+		if idx < 0 {
+			return "", "", false, nil
+		}
+		key, after = strings.TrimSpace(text[:idx]), text[idx + 1:]
+	}
+	if after != "" && after[0] != ' ' {
+		return "", "", false, nil
+	}
+	return key, strings.TrimSpace(after), true, nil
+}
+
+func (p *yamlParser) parseValue(line yamlLine) (any, error) {
+	// Parses a scalar or flow collection; flow collections may carry on over the lines that follow
+	text := line.text
+	switch {
+	case strings.HasPrefix(text, "|") || strings.HasPrefix(text, ">"):
+		return nil, yamlError(line.num, "multi-line strings aren't supported")
+	case strings.HasPrefix(text, "&") || strings.HasPrefix(text, "*") || strings.HasPrefix(text, "!"):
+		return nil, yamlError(line.num, "anchors, aliases, and tags aren't supported")
+	case strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{"):
+		for !yamlFlowClosed(text) && p.pos < len(p.lines) {
+			text += " " + p.lines[p.pos].text
+			p.pos++
+		}
+		value, rest, err := parseYamlFlow(text, line.num)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, yamlError(line.num, "unexpected '" + strings.TrimSpace(rest) + "' after value")
+		}
+		return value, nil
+	case text[0] == '"' || text[0] == '\'':
+		value, rest, err := readYamlQuoted(text, line.num)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, yamlError(line.num, "unexpected '" + strings.TrimSpace(rest) + "' after quoted string")
+		}
+		return value, nil
+	}
+	return yamlPlain{text: text, num: line.num}, nil
+}
+
+func yamlFlowClosed(text string) bool {
+	// Reports whether every bracket opened in a flow collection has been closed
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.ContainsRune(" \t:[{,", rune(text[i-1]))):
+			// As with comments, quotes only count at the start of a scalar (ex: not the apostrophe in team's)
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth <= 0 && quote == 0
+}
+
+func parseYamlFlow(text string, lineNum int) (any, string, error) {
+	// Parses one flow value from the start of text, and returns it along with the text after it
+	text = strings.TrimLeft(text, " ")
+	if text == "" {
+		return nil, "", yamlError(lineNum, "missing value")
+	}
+
+	switch text[0] {
+	case '[':
+		list := []any{}
+		rest := strings.TrimLeft(text[1:], " ")
+		for !strings.HasPrefix(rest, "]") {
+			item, after, err := parseYamlFlow(rest, lineNum)
+			if err != nil {
+				return nil, "", err
+			}
+			list = append(list, item)
+			rest, err = yamlFlowNext(after, ']', lineNum)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+		return list, rest[1:], nil
+	case '{':
+		fields := map[string]any{}
+		rest := strings.TrimLeft(text[1:], " ")
+		for !strings.HasPrefix(rest, "}") {
+			keyValue, after, err := parseYamlFlowScalar(rest, true, lineNum)
+			if err != nil {
+				return nil, "", err
+			}
+			key, isString := keyValue.(string)
+			if !isString {
+				return nil, "", yamlError(lineNum, "map keys must be strings")
+			}
+			after = strings.TrimLeft(after, " ")
+			if !strings.HasPrefix(after, ":") {
+				return nil, "", yamlError(lineNum, "expected ':' after key '" + key + "'")
+			}
+			if _, exists := fields[key]; exists {
+				return nil, "", yamlError(lineNum, "key '" + key + "' is defined more than once")
+			}
+			fields[key], after, err = parseYamlFlow(after[1:], lineNum)
+			if err != nil {
+				return nil, "", err
+			}
+			rest, err = yamlFlowNext(after, '}', lineNum)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+		return fields, rest[1:], nil
+	}
+	return parseYamlFlowScalar(text, false, lineNum)
+}
+
+func yamlFlowNext(text string, closing byte, lineNum int) (string, error) {
+	// Moves past the ',' between flow entries; what's returned starts with the next entry or the closing bracket
+	text = strings.TrimLeft(text, " ")
+	switch {
+	case strings.HasPrefix(text, ","):
+		return strings.TrimLeft(text[1:], " "), nil
+	case text != "" && text[0] == closing:
+		return text, nil
+	}
+	return "", yamlError(lineNum, "expected ',' or '" + string(closing) + "'")
+}
+
+func parseYamlFlowScalar(text string, isKey bool, lineNum int) (any, string, error) {
+	// Reads a quoted or plain scalar inside a flow collection; plain scalars end at ',', ']', '}', or ': '
+	if text[0] == '"' || text[0] == '\'' {
+		return readYamlQuoted(text, lineNum)
+	}
+	end := len(text)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == ',' || c == ']' || c == '}' {
+			end = i
+			break
+		}
+		if c == ':' && (isKey || i + 1 == len(text) || strings.ContainsRune(" ,]}", rune(text[i + 1]))) {
+			end = i
+			break
+		}
+	}
+	plain := strings.TrimSpace(text[:end])
+	if plain == "" {
+		return nil, "", yamlError(lineNum, "missing value")
+	}
+	if isKey {
+		return plain, text[end:], nil
+	}
+	return yamlPlain{text: plain, num: lineNum}, text[end:], nil
+}
+
+func readYamlQuoted(text string, lineNum int) (string, string, error) {
+	// Reads a single- or double-quoted string from the start of text, and returns it along with the text after it
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote && quote == '\'' && i + 1 < len(text) && text[i + 1] == '\'':
+			i++        // '' is an escaped single quote
+		case text[i] == quote:
+			if quote == '\'' {
+				return strings.ReplaceAll(text[1:i], "''", "'"), text[i + 1:], nil
+			}
+			value, err := strconv.Unquote(text[:i + 1])
+			if err != nil {
+				return "", "", yamlError(lineNum, "invalid escape in " + text[:i + 1])
+			}
+			return value, text[i + 1:], nil
+		}
+	}
+	return "", "", yamlError(lineNum, "unterminated quoted string")
+}
+
+func yamlToJson(value any, target reflect.Type) (any, error) {
+	// Converts a parsed YAML value into JSON-compatible values, resolving unquoted scalars against the target type
+	// Keys the target type doesn't have are converted as strings; the JSON decoder then reports them as unknown
+	for target != nil && target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		var fields map[string]reflect.Type
+		if target != nil && target.Kind() == reflect.Struct {
+			fields = jsonFieldTypes(target)
+		}
+		converted := make(map[string]any)
+		for key, item := range v {
+			var itemType reflect.Type
+			switch {
+			case fields != nil:
+				itemType = fields[key]
+			case target != nil && target.Kind() == reflect.Map:
+				itemType = target.Elem()
+			}
+			var err error
+			if converted[key], err = yamlToJson(item, itemType); err != nil {
+				return nil, err
+			}
+		}
+		return converted, nil
+	case []any:
+		var itemType reflect.Type
+		if target != nil && (target.Kind() == reflect.Slice || target.Kind() == reflect.Array) {
+			itemType = target.Elem()
+		}
+		converted := make([]any, len(v))
+		for idx, item := range v {
+			var err error
+			if converted[idx], err = yamlToJson(item, itemType); err != nil {
+				return nil, err
+			}
+		}
+		return converted, nil
+	case yamlPlain:
+		return resolveYamlPlain(v, target)
+	}
+	return value, nil
+}
+
+func jsonFieldTypes(target reflect.Type) map[string]reflect.Type {
+	// Maps the JSON names of a struct's fields (including those of embedded structs, ex: RepoBase) to their types
+	fields := make(map[string]reflect.Type)
+	for idx := 0; idx < target.NumField(); idx++ {
+		field := target.Field(idx)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embeddedName, embeddedType := range jsonFieldTypes(field.Type) {
+				fields[embeddedName] = embeddedType
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+func resolveYamlPlain(plain yamlPlain, target reflect.Type) (any, error) {
+	// Resolves an unquoted scalar to null, or to the kind of value the target type holds; anything else stays a string
+	switch plain.text {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	}
+	if target == nil {
+		return plain.text, nil
+	}
+
+	switch target.Kind() {
+	case reflect.Bool:
+		switch plain.text {
+		case "true", "True", "TRUE":
+			return true, nil
+		case "false", "False", "FALSE":
+			return false, nil
+		}
+		return nil, yamlError(plain.num, "expected true or false, not '" + plain.text + "'")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(plain.text, 10, 64)
+		if err != nil {
+			return nil, yamlError(plain.num, "expected a whole number, not '" + plain.text + "'")
+		}
+		return i, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(plain.text, 10, 64)
+		if err != nil {
+			return nil, yamlError(plain.num, "expected a whole number, not '" + plain.text + "'")
+		}
+		return u, nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(plain.text, 64)
+		if err != nil || !yamlFloatRegexp.MatchString(plain.text) {
+			return nil, yamlError(plain.num, "expected a number, not '" + plain.text + "'")
+		}
+		return f, nil
+	}
+	return plain.text, nil
+}
@@ -0,0 +1,144 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYaml(t *testing.T) {
+	tests := []struct {
+		name	string
+		input	string
+		want	any
+		wantErr	string
+	}{
+		{"empty document", "# nothing here\n", map[string]any{}, ""},
+		{"block map", "a: 1\nb: text here\n", map[string]any{"a": "1", "b": "text here"}, ""},
+		{"nested map", "a:\n  b:\n    c: x\n", map[string]any{"a": map[string]any{"b": map[string]any{"c": "x"}}}, ""},
+		{"list of scalars", "- a\n- b\n", []any{"a", "b"}, ""},
+		{"list at the key's indentation", "a:\n- x\n- y\n", map[string]any{"a": []any{"x", "y"}}, ""},
+		{"list of maps", "- k: 1\n  v: 2\n- k: 3\n", []any{map[string]any{"k": "1", "v": "2"}, map[string]any{"k": "3"}}, ""},
+		{"nested lists", "- - 1\n  - 2\n-\n  - 3\n", []any{[]any{"1", "2"}, []any{"3"}}, ""},
+		{"null values", "a:\nb: ~\nc: null\n", map[string]any{"a": nil, "b": nil, "c": nil}, ""},
+		{"comments", "a: x # comment\n# whole line\nb: 'y # not a comment'\n", map[string]any{"a": "x", "b": "y # not a comment"}, ""},
+		{"hash without a space isn't a comment", "a: x#1\n", map[string]any{"a": "x#1"}, ""},
+		{"apostrophe in plain text", "a: team's # comment\nb: x\n", map[string]any{"a": "team's", "b": "x"}, ""},
+		{"double quoted escapes", `a: "tab\there \"q\""` + "\n", map[string]any{"a": "tab\there \"q\""}, ""},
+		{"single quoted escape", "a: 'it''s'\n", map[string]any{"a": "it's"}, ""},
+		{"quoted key", "\"a b\": c\n", map[string]any{"a b": "c"}, ""},
+		{"colon without a space stays in the value", "url: http://x:8080/y\n", map[string]any{"url": "http://x:8080/y"}, ""},
+		{"flow list", "a: [x, 'y, z', \"w\"]\n", map[string]any{"a": []any{"x", "y, z", "w"}}, ""},
+		{"flow map", "a: {b: 1, c: [2, 3]}\n", map[string]any{"a": map[string]any{"b": "1", "c": []any{"2", "3"}}}, ""},
+		{"flow over several lines", "a: [x,\n  y]\nb: z\n", map[string]any{"a": []any{"x", "y"}, "b": "z"}, ""},
+		{"apostrophe in a flow list", "a: [team's, x]\nb: y\n", map[string]any{"a": []any{"team's", "x"}, "b": "y"}, ""},
+		{"empty flow collections", "a: []\nb: {}\n", map[string]any{"a": []any{}, "b": map[string]any{}}, ""},
+		{"document markers", "---\na: x\n...\n", map[string]any{"a": "x"}, ""},
+		{"bad indentation", "a: 1\n  b: 2\n", nil, "line 2: unexpected indentation"},
+		{"duplicate key", "a: 1\na: 2\n", nil, "line 2: key 'a' is defined more than once"},
+		{"tab indentation", "a:\n\t- b\n", nil, "line 2: tabs can't be used"},
+		{"multi-line string", "a: |\n  x\n", nil, "line 1: multi-line strings aren't supported"},
+		{"anchor", "a: &x 1\n", nil, "line 1: anchors, aliases, and tags aren't supported"},
+		{"unclosed flow list", "a: [1, 2\n", nil, "line 1: expected ',' or ']'"},
+		{"unterminated quote", "a: \"x\n", nil, "line 1: unterminated quoted string"},
+		{"bad escape", `a: "x\q"` + "\n", nil, "line 1: invalid escape"},
+		{"list then map", "- a\nb: 1\n", nil, "line 2: unexpected 'b: 1'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseYaml(tt.input)
+			var got any
+			if err == nil {
+				got, err = yamlToJson(parsed, nil)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseYaml: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRepoConfigFileYaml(t *testing.T) {
+	tests := []struct {
+		name	string
+		input	string
+		check	func(t *testing.T, config RepoConfigFile)
+		wantErr	string
+	}{
+		{
+			name:	"scalars resolve against the field types",
+			input:	"local:\n  - key: 2024\n    description: 2024\n    notes: 1.10\n    environments: [2024, team's]\n" +
+				"    xrayIndex: true\n    maxUniqueSnapshots: 5\nremote:\n  - key: r\n    url: https://x\n    offline: False\n" +
+				"    contentSynchronisation: {enabled: true, properties: {enabled: true}}\n",
+			check:	func(t *testing.T, config RepoConfigFile) {
+				local := config.Local[0]
+				if local.Key != "2024" || local.Description != "2024" || local.Notes != "1.10" {
+					t.Errorf("strings = %q, %q, %q; want 2024, 2024, 1.10", local.Key, local.Description, local.Notes)
+				}
+				if !reflect.DeepEqual(local.Environments, []string{"2024", "team's"}) {
+					t.Errorf("environments = %v", local.Environments)
+				}
+				if local.XrayIndex == nil || !*local.XrayIndex || local.MaxUniqueSnapshots != 5 {
+					t.Errorf("xrayIndex = %v, maxUniqueSnapshots = %d; want true, 5", local.XrayIndex, local.MaxUniqueSnapshots)
+				}
+				remote := config.Remote[0]
+				if remote.Offline == nil || *remote.Offline || remote.ContentSynchronisation == nil || !remote.ContentSynchronisation.Properties.Enabled {
+					t.Errorf("remote flags = %+v", remote)
+				}
+			},
+		},
+		{
+			name:	"unset flags stay nil",
+			input:	"local:\n  - key: images-local\n    blackedOut:\n",
+			check:	func(t *testing.T, config RepoConfigFile) {
+				if config.Local[0].BlackedOut != nil || config.Local[0].XrayIndex != nil {
+					t.Errorf("flags = %v, %v; want nil", config.Local[0].BlackedOut, config.Local[0].XrayIndex)
+				}
+			},
+		},
+		{
+			name:	"permission actions",
+			input:	"permissionTargets:\n  - name: rw\n    repo:\n      repositories: [images-local]\n      actions:\n        groups:\n          packer: [read, write]\n",
+			check:	func(t *testing.T, config RepoConfigFile) {
+				if got := config.PermissionTargets[0].Repo.Actions.Groups["packer"]; !reflect.DeepEqual(got, []string{"read", "write"}) {
+					t.Errorf("packer actions = %v", got)
+				}
+			},
+		},
+		{name: "bool field with a non-bool value", input: "local:\n  - key: a\n    xrayIndex: yes\n", wantErr: "line 3: expected true or false"},
+		{name: "number field with text", input: "local:\n  - key: a\n    maxUniqueSnapshots: many\n", wantErr: "line 3: expected a whole number"},
+		{name: "unknown setting", input: "local:\n  - key: a\n    xrayIndexx: true\n", wantErr: "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "repos.yaml")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+			config, err := ReadRepoConfigFile(filePath)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadRepoConfigFile: %v", err)
+			}
+			tt.check(t, config)
+		})
+	}
+}
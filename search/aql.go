@@ -0,0 +1,507 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// Checksums of an item as reported by Artifactory
+type Checksums struct {
+	Sha1			string
+	Md5				string
+	Sha256			string
+}
+
+// Item is a single file or folder returned from a search, with its metadata already populated
+// DownloadUri is only set for files
+// Properties is only populated when requested (ex: '.include("property.*")' for AQL)
+type Item struct {
+	Repo			string
+	Path			string
+	Name			string
+	Type			string
+	Size			int64
+	Created			time.Time
+	CreatedBy		string
+	Modified		time.Time
+	ModifiedBy		string
+	Updated			time.Time
+	Checksums		Checksums
+	DownloadUri		string
+	Properties		map[string][]string
+}
+
+// AqlRange is the paging information returned with AQL results
+type AqlRange struct {
+	StartPos		int		`json:"start_pos"`
+	EndPos			int		`json:"end_pos"`
+	Total			int		`json:"total"`
+	Limit			int		`json:"limit"`
+}
+
+// Criterion is a single typed AQL condition, built with Eq, Match, Gt, And, Or, etc.
+type Criterion interface {
+	aql() any
+	matches(item Item) bool
+}
+
+// AqlQuery builds an AQL 'items.find(...)' query
+// Ex: search.ItemsFind(search.Eq("repo", "images-local"), search.Match("name", "win2022*")).SortDesc("created").Limit(10)
+type AqlQuery struct {
+	criteria		[]Criterion
+	include			[]string
+	sortOrder		string
+	sortFields		[]string
+	offset			int
+	limit			int
+}
+
+type fieldCriterion struct {
+	field			string
+	op				string
+	value			any
+}
+
+type groupCriterion struct {
+	op				string
+	criteria		[]Criterion
+}
+
+func Prop(key string) string {
+	// Field name for a property criterion; ex: search.Eq(search.Prop("release"), "stable") --> {"@release":"stable"}
+	return "@" + key
+}
+
+func Eq(field string, value any) Criterion		{ return fieldCriterion{field, "$eq", value} }
+func Ne(field string, value any) Criterion		{ return fieldCriterion{field, "$ne", value} }
+func Gt(field string, value any) Criterion		{ return fieldCriterion{field, "$gt", value} }
+func Gte(field string, value any) Criterion		{ return fieldCriterion{field, "$gte", value} }
+func Lt(field string, value any) Criterion		{ return fieldCriterion{field, "$lt", value} }
+func Lte(field string, value any) Criterion		{ return fieldCriterion{field, "$lte", value} }
+
+// Match and NotMatch take wildcard patterns, where '*' matches any characters and '?' a single character
+func Match(field, pattern string) Criterion		{ return fieldCriterion{field, "$match", pattern} }
+func NotMatch(field, pattern string) Criterion	{ return fieldCriterion{field, "$nmatch", pattern} }
+
+// Last matches dates within a relative period ending now (ex: "30d", "2w", "12h"); Before matches dates older than it
+func Last(field, period string) Criterion		{ return fieldCriterion{field, "$last", period} }
+func Before(field, period string) Criterion		{ return fieldCriterion{field, "$before", period} }
+
+func And(criteria ...Criterion) Criterion		{ return groupCriterion{"$and", criteria} }
+func Or(criteria ...Criterion) Criterion		{ return groupCriterion{"$or", criteria} }
+
+func ItemsFind(criteria ...Criterion) *AqlQuery {
+	// Starts a new 'items.find' query; multiple criteria are combined with $and
+	return &AqlQuery{criteria: criteria}
+}
+
+func (q *AqlQuery) Include(fields ...string) *AqlQuery {
+	// Fields to return (ex: "repo", "path", "name", "created", "property.*")
+	q.include = append(q.include, fields...)
+	return q
+}
+
+func (q *AqlQuery) SortAsc(fields ...string) *AqlQuery {
+	q.sortOrder, q.sortFields = "$asc", fields
+	return q
+}
+
+func (q *AqlQuery) SortDesc(fields ...string) *AqlQuery {
+	q.sortOrder, q.sortFields = "$desc", fields
+	return q
+}
+
+func (q *AqlQuery) Offset(offset int) *AqlQuery {
+	q.offset = offset
+	return q
+}
+
+func (q *AqlQuery) Limit(limit int) *AqlQuery {
+	q.limit = limit
+	return q
+}
+
+func aqlValue(value any) any {
+	// Dates are sent in the ISO-8601 format Artifactory expects
+	if t, ok := value.(time.Time); ok {
+		return t.UTC().Format("2006-01-02T15:04:05.000Z")
+	}
+	return value
+}
+
+func (c fieldCriterion) aql() any {
+	if c.op == "$eq" {
+		return map[string]any{c.field: aqlValue(c.value)}
+	}
+	return map[string]any{c.field: map[string]any{c.op: aqlValue(c.value)}}
+}
+
+func (c groupCriterion) aql() any {
+	list := make([]any, 0, len(c.criteria))
+	for _, criterion := range c.criteria {
+		list = append(list, criterion.aql())
+	}
+	return map[string]any{c.op: list}
+}
+
+func (q *AqlQuery) String() string {
+	// Renders the query as AQL text, ready to POST to /api/search/aql
+	var criteria any = map[string]any{}
+	if len(q.criteria) == 1 {
+		criteria = q.criteria[0].aql()
+	} else if len(q.criteria) > 1 {
+		criteria = And(q.criteria...).aql()
+	}
+	jsonCriteria, _ := json.Marshal(criteria)
+	query := "items.find(" + string(jsonCriteria) + ")"
+
+	if len(q.include) != 0 {
+		quoted := make([]string, len(q.include))
+		for idx, field := range q.include {
+			quoted[idx] = strconv.Quote(field)
+		}
+		query = query + ".include(" + strings.Join(quoted, ",") + ")"
+	}
+	if len(q.sortFields) != 0 {
+		jsonSort, _ := json.Marshal(map[string][]string{q.sortOrder: q.sortFields})
+		query = query + ".sort(" + string(jsonSort) + ")"
+	}
+	if q.offset > 0 {
+		query = query + ".offset(" + strconv.Itoa(q.offset) + ")"
+	}
+	if q.limit > 0 {
+		query = query + ".limit(" + strconv.Itoa(q.limit) + ")"
+	}
+	return query
+}
+
+func ExecuteAql(q *AqlQuery) ([]Item, AqlRange, error) {
+	// POSTs the query to /api/search/aql and decodes the results into typed items
+	common.LogTxtHandler().Info(">>> Executing AQL Query...")
+	if q == nil {
+		err := errors.New("Unable to search for Artifacts without an AQL query.")
+		common.LogTxtHandler().Error("Unable to search for Artifacts without an AQL query.")
+		return nil, AqlRange{}, err
+	}
+	return executeAqlString(q.String())
+}
+
+func executeAqlString(query string) ([]Item, AqlRange, error) {
+	requestPath := util.ServerApi + "/search/aql"
+	common.LogTxtHandler().Debug("AQL QUERY: " + query)
+
+	body, status, err := common.SendRequest("POST", requestPath, strings.NewReader(query), "text/plain")
+	if err != nil {
+		return nil, AqlRange{}, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("AQL search failed with status code " + fmt.Sprint(status) + ": " + string(body))
+		common.LogTxtHandler().Error("AQL search failed with status code " + fmt.Sprint(status))
+		return nil, AqlRange{}, err
+	}
+
+	type resultsJson struct {
+		Results []struct {
+			Repo			string	`json:"repo"`
+			Path			string	`json:"path"`
+			Name			string	`json:"name"`
+			Type			string	`json:"type"`
+			Size			int64	`json:"size"`
+			Created			string	`json:"created"`
+			CreatedBy		string	`json:"created_by"`
+			Modified		string	`json:"modified"`
+			ModifiedBy		string	`json:"modified_by"`
+			Updated			string	`json:"updated"`
+			ActualSha1		string	`json:"actual_sha1"`
+			ActualMd5		string	`json:"actual_md5"`
+			Sha256			string	`json:"sha256"`
+			Properties		[]struct {
+				Key			string	`json:"key"`
+				Value		string	`json:"value"`
+			}	`json:"properties"`
+		}	`json:"results"`
+		Range	AqlRange	`json:"range"`
+	}
+
+	var jsonData resultsJson
+	err = json.Unmarshal(body, &jsonData)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, AqlRange{}, err
+	}
+
+	items := make([]Item, 0, len(jsonData.Results))
+	for _, r := range jsonData.Results {
+		item := Item{
+			Repo: r.Repo, Path: r.Path, Name: r.Name, Type: r.Type, Size: r.Size,
+			CreatedBy: r.CreatedBy, ModifiedBy: r.ModifiedBy,
+			Checksums: Checksums{Sha1: r.ActualSha1, Md5: r.ActualMd5, Sha256: r.Sha256},
+		}
+		item.Created = parseAqlResultTime(item, "created", r.Created)
+		item.Modified = parseAqlResultTime(item, "modified", r.Modified)
+		item.Updated = parseAqlResultTime(item, "updated", r.Updated)
+		if r.Type != "folder" {
+			item.DownloadUri = setDownloadUri(r.Repo, r.Path, r.Name)
+		}
+		if len(r.Properties) != 0 {
+			item.Properties = make(map[string][]string)
+			for _, p := range r.Properties {
+				item.Properties[p.Key] = append(item.Properties[p.Key], p.Value)
+			}
+		}
+		items = append(items, item)
+	}
+	common.LogTxtHandler().Debug("AQL RESULTS RETURNED: " + strconv.Itoa(len(items)))
+	return items, jsonData.Range, nil
+}
+
+func parseAqlTime(value string) (time.Time, error) {
+	return common.ParseArtifTime(value)
+}
+
+func parseAqlResultTime(item Item, field, value string) time.Time {
+	// Fields that weren't included come back empty (the zero time); anything else that won't parse is logged
+	t, err := parseAqlTime(value)
+	if err != nil {
+		strErr := fmt.Sprintf("%v", err)
+		common.LogTxtHandler().Warn("Unable to parse '" + field + "' date of " + item.Repo + "/" + item.Path + "/" + item.Name + " - " + strErr)
+	}
+	return t
+}
+
+func setDownloadUri(repo, itemPath, name string) string {
+	// Forms the download URI (http://server.com:8081/artifactory/repo-key/folder/artifact.ext) of a file
+	downloadUri := strings.TrimSuffix(common.TrimEndSlashUrl(util.ServerApi), "/api") + "/" + repo
+	if itemPath != "" && itemPath != "." {
+		downloadUri = downloadUri + "/" + strings.Trim(itemPath, "/")
+	}
+	return downloadUri + "/" + name
+}
+
+func (i Item) Uri() string {
+	// Artifact URI (http://server.com:8081/artifactory/api/storage/repo-key/folder/artifact.ext) of the item
+	return common.SetArtifUriFromRepoPathName("", i.Repo, i.Path, i.Name)
+}
+
+// --> The following support evaluating a query locally against a list of items, such as from a fake/test server
+// Supported: all field and property criteria above, $and/$or, sort, offset, and limit
+
+func (q *AqlQuery) Evaluate(items []Item) []Item {
+	// Applies the query's criteria, sort, offset, and limit to the items, as Artifactory would
+	var results []Item
+	for _, item := range items {
+		matched := true
+		for _, c := range q.criteria {
+			if !c.matches(item) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			results = append(results, item)
+		}
+	}
+
+	if len(q.sortFields) != 0 {
+		sort.SliceStable(results, func(i, j int) bool {
+			for _, field := range q.sortFields {
+				cmp := compareValues(fieldValue(results[i], field), fieldValue(results[j], field))
+				if cmp != 0 {
+					if q.sortOrder == "$desc" {
+						return cmp > 0
+					}
+					return cmp < 0
+				}
+			}
+			return false
+		})
+	}
+
+	if q.offset > 0 {
+		results = results[min(q.offset, len(results)):]
+	}
+	if q.limit > 0 && len(results) > q.limit {
+		results = results[:q.limit]
+	}
+	return results
+}
+
+func (c groupCriterion) matches(item Item) bool {
+	for _, criterion := range c.criteria {
+		matched := criterion.matches(item)
+		if c.op == "$or" && matched {
+			return true
+		}
+		if c.op == "$and" && !matched {
+			return false
+		}
+	}
+	return c.op == "$and"
+}
+
+func (c fieldCriterion) matches(item Item) bool {
+	if strings.HasPrefix(c.field, "@") {
+		// Properties can have multiple values; negative operators require none of them to match
+		values := item.Properties[strings.TrimPrefix(c.field, "@")]
+		negative := c.op == "$ne" || c.op == "$nmatch"
+		for _, v := range values {
+			matched := compareField(v, c.op, c.value)
+			if negative && !matched {
+				return false
+			}
+			if !negative && matched {
+				return true
+			}
+		}
+		return negative
+	}
+	return compareField(fieldValue(item, c.field), c.op, c.value)
+}
+
+func fieldValue(item Item, field string) any {
+	switch field {
+	case "repo":
+		return item.Repo
+	case "path":
+		return item.Path
+	case "name":
+		return item.Name
+	case "type":
+		return item.Type
+	case "size":
+		return item.Size
+	case "created":
+		return item.Created
+	case "created_by":
+		return item.CreatedBy
+	case "modified":
+		return item.Modified
+	case "modified_by":
+		return item.ModifiedBy
+	case "updated":
+		return item.Updated
+	case "actual_sha1":
+		return item.Checksums.Sha1
+	case "actual_md5":
+		return item.Checksums.Md5
+	case "sha256":
+		return item.Checksums.Sha256
+	}
+	if strings.HasPrefix(field, "@") {
+		values := item.Properties[strings.TrimPrefix(field, "@")]
+		return strings.Join(values, ",")
+	}
+	return nil
+}
+
+func compareField(actual any, op string, expected any) bool {
+	switch op {
+	case "$match", "$nmatch":
+		pattern := wildcardToRegexp(fmt.Sprint(expected))
+		matched := pattern.MatchString(fmt.Sprint(actual))
+		return matched == (op == "$match")
+	case "$last", "$before":
+		t, ok := actual.(time.Time)
+		period, err := ParseRelativePeriod(fmt.Sprint(expected))
+		if !ok || err != nil || t.IsZero() {
+			return false
+		}
+		cutoff := time.Now().Add(-period)
+		return t.After(cutoff) == (op == "$last")
+	}
+
+	cmp := compareValues(actual, expected)
+	switch op {
+	case "$eq":
+		return cmp == 0
+	case "$ne":
+		return cmp != 0
+	case "$gt":
+		return cmp > 0
+	case "$gte":
+		return cmp >= 0
+	case "$lt":
+		return cmp < 0
+	case "$lte":
+		return cmp <= 0
+	}
+	return false
+}
+
+func compareValues(a, b any) int {
+	// Compares numbers numerically, dates chronologically, and everything else as strings
+	switch av := a.(type) {
+	case int64:
+		bv, err := strconv.ParseInt(fmt.Sprint(b), 10, 64)
+		if err == nil {
+			return cmpOrdered(av, bv)
+		}
+	case time.Time:
+		var bv time.Time
+		switch t := b.(type) {
+		case time.Time:
+			bv = t
+		default:
+			parsed, err := parseAqlTime(fmt.Sprint(b))
+			if err != nil {
+				strErr := fmt.Sprintf("%v", err)
+				common.LogTxtHandler().Warn("Unable to parse date '" + fmt.Sprint(b) + "' in query; comparing as text - " + strErr)
+				return strings.Compare(av.Format(time.RFC3339), fmt.Sprint(b))
+			}
+			bv = parsed
+		}
+		return av.Compare(bv)
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func cmpOrdered(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func wildcardToRegexp(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+	return regexp.MustCompile("(?s)^" + quoted + "$")
+}
+
+func ParseRelativePeriod(period string) (time.Duration, error) {
+	// Parses AQL relative periods such as "30d", "2w", "12h", "3mo", "1y", "45minutes", "10s"
+	// Months and years are approximated as 30 and 365 days
+	units := []struct {
+		suffix		string
+		duration	time.Duration
+	}{
+		{"minutes", time.Minute}, {"mo", 30 * 24 * time.Hour}, {"ms", time.Millisecond},
+		{"y", 365 * 24 * time.Hour}, {"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour},
+		{"h", time.Hour}, {"s", time.Second},
+	}
+	period = strings.TrimSpace(period)
+	for _, u := range units {
+		if strings.HasSuffix(period, u.suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(period, u.suffix))
+			if err != nil {
+				break
+			}
+			return time.Duration(n) * u.duration, nil
+		}
+	}
+	return 0, errors.New("Invalid relative period '" + period + "'. Use a number followed by y, mo, w, d, h, minutes, s, or ms.")
+}
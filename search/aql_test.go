@@ -0,0 +1,132 @@
+package search
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+func testItems() []Item {
+	now := time.Now().UTC()
+	return []Item{
+		{Repo: "images-local", Path: "windows/win2022", Name: "win2022.ova", Type: "file", Size: 300,
+			Created: now.Add(-48 * time.Hour), Properties: map[string][]string{"release": {"stable"}, "os": {"windows", "server"}}},
+		{Repo: "images-local", Path: "windows/win2019", Name: "win2019.ova", Type: "file", Size: 100,
+			Created: now.Add(-90 * 24 * time.Hour), Properties: map[string][]string{"release": {"deprecated"}, "os": {"windows"}}},
+		{Repo: "images-local", Path: "linux/rhel9", Name: "rhel9.ova", Type: "file", Size: 200,
+			Created: now.Add(-10 * 24 * time.Hour), Properties: map[string][]string{"release": {"stable"}, "os": {"linux"}}},
+		{Repo: "images-dev", Path: "linux/rhel9", Name: "rhel9.ovf", Type: "file", Size: 50,
+			Created: now.Add(-time.Hour)},
+		{Repo: "images-local", Path: "linux", Name: "rhel9", Type: "folder",
+			Created: now.Add(-10 * 24 * time.Hour)},
+	}
+}
+
+func names(items []Item) []string {
+	var list []string
+	for _, item := range items {
+		list = append(list, item.Name)
+	}
+	return list
+}
+
+func TestEvaluate(t *testing.T) {
+	cutoff := time.Now().UTC().Add(-30 * 24 * time.Hour)
+
+	tests := []struct {
+		name	string
+		query	*AqlQuery
+		want	[]string
+	}{
+		{"no criteria", ItemsFind(), []string{"win2022.ova", "win2019.ova", "rhel9.ova", "rhel9.ovf", "rhel9"}},
+		{"eq", ItemsFind(Eq("repo", "images-dev")), []string{"rhel9.ovf"}},
+		{"multiple criteria are and-ed", ItemsFind(Eq("repo", "images-local"), Eq("type", "file")), []string{"win2022.ova", "win2019.ova", "rhel9.ova"}},
+		{"$and", ItemsFind(And(Match("name", "*.ova"), Gt("size", 150))), []string{"win2022.ova", "rhel9.ova"}},
+		{"$or", ItemsFind(Or(Eq("name", "win2019.ova"), Eq("repo", "images-dev"))), []string{"win2019.ova", "rhel9.ovf"}},
+		{"$or inside $and", ItemsFind(Eq("type", "file"), Or(Match("path", "linux/*"), Lt("size", 150))), []string{"win2019.ova", "rhel9.ova", "rhel9.ovf"}},
+		{"$match", ItemsFind(Match("name", "rhel?.ov*")), []string{"rhel9.ova", "rhel9.ovf"}},
+		{"$match is anchored", ItemsFind(Match("name", "rhel")), nil},
+		{"$nmatch", ItemsFind(Eq("type", "file"), NotMatch("path", "windows/*")), []string{"rhel9.ova", "rhel9.ovf"}},
+		{"$ne", ItemsFind(Ne("repo", "images-local")), []string{"rhel9.ovf"}},
+		{"size compares numerically", ItemsFind(Gte("size", "100"), Lte("size", 200)), []string{"win2019.ova", "rhel9.ova"}},
+		{"property eq", ItemsFind(Eq(Prop("release"), "stable")), []string{"win2022.ova", "rhel9.ova"}},
+		{"property matches any value", ItemsFind(Eq(Prop("os"), "server")), []string{"win2022.ova"}},
+		{"property ne needs no value to match", ItemsFind(Eq("type", "file"), Ne(Prop("os"), "windows")), []string{"rhel9.ova", "rhel9.ovf"}},
+		{"property match", ItemsFind(Match(Prop("release"), "dep*")), []string{"win2019.ova"}},
+		{"date after time.Time", ItemsFind(Eq("type", "file"), Gt("created", cutoff)), []string{"win2022.ova", "rhel9.ova", "rhel9.ovf"}},
+		{"date before AQL string", ItemsFind(Lt("created", cutoff.Format("2006-01-02T15:04:05.000Z"))), []string{"win2019.ova"}},
+		{"$last", ItemsFind(Last("created", "3d")), []string{"win2022.ova", "rhel9.ovf"}},
+		{"$before", ItemsFind(Before("created", "4w")), []string{"win2019.ova"}},
+		{"sort asc", ItemsFind(Eq("type", "file")).SortAsc("size"), []string{"rhel9.ovf", "win2019.ova", "rhel9.ova", "win2022.ova"}},
+		{"sort desc by date", ItemsFind(Eq("type", "file")).SortDesc("created"), []string{"rhel9.ovf", "win2022.ova", "rhel9.ova", "win2019.ova"}},
+		{"sort by several fields", ItemsFind(Eq("type", "file")).SortAsc("repo", "name"), []string{"rhel9.ovf", "rhel9.ova", "win2019.ova", "win2022.ova"}},
+		{"limit", ItemsFind(Eq("type", "file")).SortDesc("size").Limit(2), []string{"win2022.ova", "rhel9.ova"}},
+		{"offset and limit", ItemsFind(Eq("type", "file")).SortDesc("size").Offset(1).Limit(2), []string{"rhel9.ova", "win2019.ova"}},
+		{"offset past the end", ItemsFind().Offset(10), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(tt.query.Evaluate(testItems()))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("%s\n got %v\nwant %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteAql(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/search/aql" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		gotQuery = string(body)
+		w.Write([]byte(`{"results":[
+			{"repo":"images-local","path":"windows","name":"win2022.ova","type":"file","size":300,
+			 "created":"2024-10-01T14:03:21.123-04:00","modified":"not a date","actual_sha1":"abc",
+			 "properties":[{"key":"os","value":"windows"},{"key":"os","value":"server"}]},
+			{"repo":"images-local","path":".","name":"windows","type":"folder"}
+		],"range":{"start_pos":0,"end_pos":2,"total":2}}`))
+	}))
+	defer srv.Close()
+	serverApi := util.ServerApi
+	util.ServerApi = srv.URL + "/api"
+	defer func() { util.ServerApi = serverApi }()
+
+	q := ItemsFind(Eq("repo", "images-local")).Include("repo", "path", "name", "property.*").SortAsc("name").Limit(5)
+	items, aqlRange, err := ExecuteAql(q)
+	if err != nil {
+		t.Fatalf("ExecuteAql: %v", err)
+	}
+	if want := `items.find({"repo":"images-local"}).include("repo","path","name","property.*").sort({"$asc":["name"]}).limit(5)`; gotQuery != want {
+		t.Errorf("query sent\n got %s\nwant %s", gotQuery, want)
+	}
+	if len(items) != 2 || aqlRange.Total != 2 {
+		t.Fatalf("got %d items (total %d), want 2", len(items), aqlRange.Total)
+	}
+
+	file := items[0]
+	if want := time.Date(2024, 10, 1, 18, 3, 21, 123000000, time.UTC); !file.Created.Equal(want) {
+		t.Errorf("created = %v, want %v", file.Created, want)
+	}
+	if !file.Modified.IsZero() {
+		t.Errorf("unparseable modified date = %v, want zero time", file.Modified)
+	}
+	if !slices.Equal(file.Properties["os"], []string{"windows", "server"}) {
+		t.Errorf("os property = %v, want [windows server]", file.Properties["os"])
+	}
+	if want := srv.URL + "/images-local/windows/win2022.ova"; file.DownloadUri != want {
+		t.Errorf("download URI = %s, want %s", file.DownloadUri, want)
+	}
+	if items[1].DownloadUri != "" {
+		t.Errorf("folder has download URI %s, want none", items[1].DownloadUri)
+	}
+}
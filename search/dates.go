@@ -0,0 +1,252 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// Date fields supported by 'GetArtifactsByDates'
+const (
+	DateCreated			= "created"
+	DateLastModified	= "lastModified"
+	DateLastDownloaded	= "lastDownloaded"
+)
+
+type DateResult struct {
+	Uri				string
+	Created			time.Time
+	LastModified	time.Time
+	LastDownloaded	time.Time
+}
+
+type UsageResult struct {
+	Uri						string
+	LastDownloaded			time.Time
+	DownloadCount			int
+	RemoteLastDownloaded	time.Time
+	RemoteDownloadCount		int
+}
+
+func toEpochMillis(t time.Time) string {
+	// The search APIs take times as milliseconds since the epoch
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
+
+func getDateSearchResults(requestPath string, results any) error {
+	// Sends a date/usage search request and unmarshals the results
+	// A 404 from these APIs means nothing matched, which the caller reports as ErrNoResults
+	body, status, err := common.SendRequest("GET", requestPath, nil, "")
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return nil
+	}
+	if status != http.StatusOK {
+		err := errors.New("Search failed with status code " + fmt.Sprint(status))
+		common.LogTxtHandler().Error("Search failed with status code " + fmt.Sprint(status))
+		return err
+	}
+
+	err = json.Unmarshal(body, results)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return err
+	}
+	return nil
+}
+
+func parseResultTime(uri, field, value string) time.Time {
+	// Timestamps that can't be parsed are logged and left as the zero time rather than failing the whole search
+	t, err := parseAqlTime(value)
+	if err != nil {
+		common.LogTxtHandler().Warn("Unable to parse " + field + " time '" + value + "' for: " + uri)
+	}
+	return t
+}
+
+func GetArtifactsCreatedBetween(from, to time.Time, scope ...SearchScope) ([]DateResult, error) {
+	// Returns artifacts created between 'from' and 'to' (inclusive); a zero 'to' means now
+	// Optionally takes a scope to limit the search to certain repos and a path within them
+	searchScope := getScope(scope)
+	common.LogTxtHandler().Info(">>> Getting Artifacts Created Between: " + from.String() + " and " + to.String() + "...")
+
+	if from.IsZero() {
+		err := errors.New("Unable to search by creation date without a start date.")
+		common.LogTxtHandler().Error("Unable to search by creation date without a start date.")
+		return nil, err
+	}
+
+	requestPath := util.ServerApi + "/search/creation?from=" + toEpochMillis(from)
+	if !to.IsZero() {
+		requestPath = requestPath + "&to=" + toEpochMillis(to)
+	}
+	requestPath = requestPath + searchScope.reposParam()
+
+	type resultsJson struct {
+		Results []struct {
+			Uri		string	`json:"uri"`
+			Created	string	`json:"created"`
+		}	`json:"results"`
+	}
+
+	var jsonData resultsJson
+	err := getDateSearchResults(requestPath, &jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []DateResult
+	for _, r := range jsonData.Results {
+		if !searchScope.InScope(r.Uri) {
+			continue
+		}
+		results = append(results, DateResult{Uri: r.Uri, Created: parseResultTime(r.Uri, DateCreated, r.Created)})
+	}
+	if len(results) == 0 {
+		common.LogTxtHandler().Warn("No results returned")
+		return nil, ErrNoResults
+	}
+	common.LogTxtHandler().Info("Found " + strconv.Itoa(len(results)) + " artifact(s).")
+	return results, nil
+}
+
+func GetArtifactsByDates(dateFields []string, from, to time.Time, scope ...SearchScope) ([]DateResult, error) {
+	// Returns artifacts where ANY of the given date fields ('created', 'lastModified', 'lastDownloaded') falls between 'from' and 'to'
+	// A zero 'from' means from the beginning and a zero 'to' means now
+	// Optionally takes a scope to limit the search to certain repos and a path within them
+	searchScope := getScope(scope)
+	common.LogTxtHandler().Info(">>> Getting Artifacts by Dates: " + strings.Join(dateFields, ",") + "...")
+
+	if len(dateFields) == 0 {
+		err := errors.New("Unable to search by dates without at least one date field.")
+		common.LogTxtHandler().Error("Unable to search by dates without at least one date field.")
+		return nil, err
+	}
+	for _, field := range dateFields {
+		if !slices.Contains([]string{DateCreated, DateLastModified, DateLastDownloaded}, field) {
+			err := errors.New("Unsupported date field '" + field + "'. Supported fields are 'created', 'lastModified', and 'lastDownloaded'.")
+			common.LogTxtHandler().Error("Unsupported date field '" + field + "'. Supported fields are 'created', 'lastModified', and 'lastDownloaded'.")
+			return nil, err
+		}
+	}
+
+	requestPath := util.ServerApi + "/search/dates?dateFields=" + strings.Join(dateFields, ",")
+	if !from.IsZero() {
+		requestPath = requestPath + "&from=" + toEpochMillis(from)
+	}
+	if !to.IsZero() {
+		requestPath = requestPath + "&to=" + toEpochMillis(to)
+	}
+	requestPath = requestPath + searchScope.reposParam()
+
+	type resultsJson struct {
+		Results []struct {
+			Uri				string	`json:"uri"`
+			Created			string	`json:"created"`
+			LastModified	string	`json:"lastModified"`
+			LastDownloaded	string	`json:"lastDownloaded"`
+		}	`json:"results"`
+	}
+
+	var jsonData resultsJson
+	err := getDateSearchResults(requestPath, &jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []DateResult
+	for _, r := range jsonData.Results {
+		if !searchScope.InScope(r.Uri) {
+			continue
+		}
+		results = append(results, DateResult{
+			Uri: r.Uri,
+			Created: parseResultTime(r.Uri, DateCreated, r.Created),
+			LastModified: parseResultTime(r.Uri, DateLastModified, r.LastModified),
+			LastDownloaded: parseResultTime(r.Uri, DateLastDownloaded, r.LastDownloaded),
+		})
+	}
+	if len(results) == 0 {
+		common.LogTxtHandler().Warn("No results returned")
+		return nil, ErrNoResults
+	}
+	common.LogTxtHandler().Info("Found " + strconv.Itoa(len(results)) + " artifact(s).")
+	return results, nil
+}
+
+func GetArtifactsModifiedSince(since time.Time, scope ...SearchScope) ([]DateResult, error) {
+	// Returns artifacts last modified at or after 'since'
+	if since.IsZero() {
+		err := errors.New("Unable to search by modified date without a start date.")
+		common.LogTxtHandler().Error("Unable to search by modified date without a start date.")
+		return nil, err
+	}
+	return GetArtifactsByDates([]string{DateLastModified}, since, time.Time{}, scope...)
+}
+
+func GetArtifactsNotDownloadedSince(notUsedSince, createdBefore time.Time, scope ...SearchScope) ([]UsageResult, error) {
+	// Returns artifacts that haven't been downloaded since 'notUsedSince', including those never downloaded
+	// 'createdBefore' optionally excludes artifacts created after that time, so newly uploaded artifacts aren't included; a zero value skips this
+	// Optionally takes a scope to limit the search to certain repos and a path within them
+	searchScope := getScope(scope)
+	common.LogTxtHandler().Info(">>> Getting Artifacts Not Downloaded Since: " + notUsedSince.String() + "...")
+
+	if notUsedSince.IsZero() {
+		err := errors.New("Unable to search by usage without a 'not used since' date.")
+		common.LogTxtHandler().Error("Unable to search by usage without a 'not used since' date.")
+		return nil, err
+	}
+
+	requestPath := util.ServerApi + "/search/usage?notUsedSince=" + toEpochMillis(notUsedSince)
+	if !createdBefore.IsZero() {
+		requestPath = requestPath + "&createdBefore=" + toEpochMillis(createdBefore)
+	}
+	requestPath = requestPath + searchScope.reposParam()
+
+	type resultsJson struct {
+		Results []struct {
+			Uri						string	`json:"uri"`
+			LastDownloaded			string	`json:"lastDownloaded"`
+			DownloadCount			int		`json:"downloadCount"`
+			RemoteLastDownloaded	string	`json:"remoteLastDownloaded"`
+			RemoteDownloadCount		int		`json:"remoteDownloadCount"`
+		}	`json:"results"`
+	}
+
+	var jsonData resultsJson
+	err := getDateSearchResults(requestPath, &jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []UsageResult
+	for _, r := range jsonData.Results {
+		if !searchScope.InScope(r.Uri) {
+			continue
+		}
+		results = append(results, UsageResult{
+			Uri: r.Uri,
+			LastDownloaded: parseResultTime(r.Uri, DateLastDownloaded, r.LastDownloaded),
+			DownloadCount: r.DownloadCount,
+			RemoteLastDownloaded: parseResultTime(r.Uri, "remoteLastDownloaded", r.RemoteLastDownloaded),
+			RemoteDownloadCount: r.RemoteDownloadCount,
+		})
+	}
+	if len(results) == 0 {
+		common.LogTxtHandler().Warn("No results returned")
+		return nil, ErrNoResults
+	}
+	common.LogTxtHandler().Info("Found " + strconv.Itoa(len(results)) + " artifact(s).")
+	return results, nil
+}
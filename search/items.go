@@ -0,0 +1,211 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// Number of artifacts looked up per AQL query in 'GetItemsForList'
+const itemBatchSize = 100
+
+func getItemsWithDetail(requestPath string, includeProps bool, searchScope SearchScope) ([]Item, error) {
+	// Sends a quick search request asking for full item info (and optionally properties) with each result,
+	// so no follow-up request per artifact is needed
+	// Returns ErrNoResults if nothing matches within the search scope
+	detail := "info"
+	if includeProps {
+		detail = "info, properties"
+	}
+
+	common.LogTxtHandler().Debug("REQUEST: Sending 'GET' request to: " + requestPath)
+	request, err := http.NewRequest("GET", requestPath, nil)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error creating request. " + strErr)
+		return nil, err
+	}
+	request.Header.Add("Authorization", common.SetBearer(util.Token))
+	request.Header.Add("X-Result-Detail", detail)
+
+	client := common.NewClient()
+	response, err := client.Do(request)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error on response. " + strErr)
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error reading response. " + strErr)
+		return nil, err
+	}
+	common.LogTxtHandler().Debug("REQUEST RESPONSE: " + string(body))
+
+	if response.StatusCode == http.StatusNotFound {
+		common.LogTxtHandler().Warn("No results returned")
+		return nil, ErrNoResults
+	}
+	if response.StatusCode != http.StatusOK {
+		err := errors.New("Search failed with status code " + fmt.Sprint(response.StatusCode))
+		common.LogTxtHandler().Error("Search failed with status code " + fmt.Sprint(response.StatusCode))
+		return nil, err
+	}
+
+	// Each result is the same as the artifact's storage info, plus its properties if requested
+	type resultsJson struct {
+		Results []struct {
+			Uri				string	`json:"uri"`
+			DownloadUri		string	`json:"downloadUri"`
+			Created			string	`json:"created"`
+			CreatedBy		string	`json:"createdBy"`
+			LastModified	string	`json:"lastModified"`
+			ModifiedBy		string	`json:"modifiedBy"`
+			LastUpdated		string	`json:"lastUpdated"`
+			Size			string	`json:"size"`
+			Checksums		struct {
+				Sha1		string	`json:"sha1"`
+				Md5			string	`json:"md5"`
+				Sha256		string	`json:"sha256"`
+			}	`json:"checksums"`
+			Properties		map[string][]string	`json:"properties"`
+		}	`json:"results"`
+	}
+
+	var jsonData resultsJson
+	err = json.Unmarshal(body, &jsonData)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, err
+	}
+
+	items := []Item{}
+	for _, r := range jsonData.Results {
+		if !searchScope.InScope(r.Uri) {
+			continue
+		}
+		repo, itemPath, name := common.ParseArtifUriForRepoPathName("", r.Uri)
+		item := Item{
+			Repo: repo, Path: itemPath, Name: name, Type: "file",
+			CreatedBy: r.CreatedBy, ModifiedBy: r.ModifiedBy, DownloadUri: r.DownloadUri,
+			Checksums: Checksums{Sha1: r.Checksums.Sha1, Md5: r.Checksums.Md5, Sha256: r.Checksums.Sha256},
+			Properties: r.Properties,
+		}
+		if r.Size != "" {
+			item.Size, _ = strconv.ParseInt(r.Size, 10, 64)
+		}
+		item.Created = parseResultTime(r.Uri, "created", r.Created)
+		item.Modified = parseResultTime(r.Uri, "lastModified", r.LastModified)
+		item.Updated = parseResultTime(r.Uri, "lastUpdated", r.LastUpdated)
+		items = append(items, item)
+		common.LogTxtHandler().Debug("FOUND ARTIFACT: " + r.Uri)
+	}
+	if len(items) == 0 {
+		common.LogTxtHandler().Warn("No results returned")
+		return nil, ErrNoResults
+	}
+	common.LogTxtHandler().Info("Found " + strconv.Itoa(len(items)) + " artifact(s).")
+	return items, nil
+}
+
+func GetItemsByName(artifName string, includeProps bool, scope ...SearchScope) ([]Item, error) {
+	// Same search as 'GetArtifactsByName', but returns each artifact's metadata rather than just its URI
+	// Properties are only populated if includeProps is true
+	searchScope := getScope(scope)
+	common.LogTxtHandler().Info(">>> Getting Items by Name...")
+
+	if artifName == "" {
+		err := errors.New("Unable to search for Artifact without at least a partial Artifact name.")
+		common.LogTxtHandler().Error("Unable to search for Artifact without at least a partial Artifact name.")
+		return nil, err
+	}
+
+	requestPath := util.ServerApi + "/search/artifact?name=" + artifName + searchScope.reposParam()
+	return getItemsWithDetail(requestPath, includeProps, searchScope)
+}
+
+func GetItemsByProps(listKvProps []string, includeProps bool, scope ...SearchScope) ([]Item, error) {
+	// Same search as 'GetArtifactsByProps', but returns each artifact's metadata rather than just its URI
+	// Properties are only populated if includeProps is true
+	searchScope := getScope(scope)
+	common.LogTxtHandler().Info(">>> Getting Items by Property Names/Values...")
+
+	if len(listKvProps) == 0 {
+		err := errors.New("Unable to search by Property without at least one Property Name and, optionally, Value")
+		common.LogTxtHandler().Error("Unable to search by Property without at least one Property Name and, optionally, Value")
+		return nil, err
+	}
+
+	requestPath := util.ServerApi + "/search/prop?" + strings.Join(listKvProps, "&") + searchScope.reposParam()
+	return getItemsWithDetail(requestPath, includeProps, searchScope)
+}
+
+func GetItemsForList(listArtifUris []string, includeProps bool) ([]Item, error) {
+	// Looks up the metadata for a list of artifact URIs (ex: from another search) with AQL, 100 artifacts per query
+	// Artifacts that no longer exist are left out; results are in the same order as the list
+	common.LogTxtHandler().Info(">>> Getting Items for List of Artifacts...")
+
+	if len(listArtifUris) == 0 {
+		err := errors.New("List of artifacts cannot be empty.")
+		common.LogTxtHandler().Error("List of artifacts cannot be empty.")
+		return nil, err
+	}
+
+	refs := make([]Item, len(listArtifUris))
+	for idx, artifUri := range listArtifUris {
+		refs[idx].Repo, refs[idx].Path, refs[idx].Name = common.ParseArtifUriForRepoPathName("", artifUri)
+	}
+	fields := []string{"repo", "path", "name", "type", "size", "created", "created_by", "modified", "modified_by", "updated",
+		"actual_sha1", "actual_md5", "sha256"}
+	if includeProps {
+		fields = append(fields, "property.*")
+	}
+	found, err := findItems(refs, fields...)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []Item{}
+	for idx, artifUri := range listArtifUris {
+		if item, exists := found[itemKey(refs[idx])]; exists {
+			items = append(items, item)
+		} else {
+			common.LogTxtHandler().Warn("Artifact not found: " + artifUri)
+		}
+	}
+	return items, nil
+}
+
+func itemKey(item Item) string {
+	return item.Repo + "/" + item.Path + "/" + item.Name
+}
+
+func findItems(refs []Item, fields ...string) (map[string]Item, error) {
+	// Looks up items by repo, path, and name with AQL, itemBatchSize items per query, returning the given fields
+	// Results are keyed by 'repo/path/name'; items that don't exist are left out
+	found := make(map[string]Item)
+	for start := 0; start < len(refs); start += itemBatchSize {
+		var criteria []Criterion
+		for _, ref := range refs[start:min(start + itemBatchSize, len(refs))] {
+			criteria = append(criteria, And(Eq("repo", ref.Repo), Eq("path", ref.Path), Eq("name", ref.Name)))
+		}
+		items, _, err := executeAqlString(ItemsFind(Or(criteria...)).Include(fields...).String())
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			found[itemKey(item)] = item
+		}
+	}
+	return found, nil
+}
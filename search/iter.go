@@ -0,0 +1,137 @@
+package search
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+)
+
+const defaultPageSize = 500
+
+func (q *AqlQuery) clone() *AqlQuery {
+	c := *q
+	c.criteria = slices.Clone(q.criteria)
+	c.include = slices.Clone(q.include)
+	c.sortFields = slices.Clone(q.sortFields)
+	return &c
+}
+
+func IterAql(q *AqlQuery, pageSize int) iter.Seq2[Item, error] {
+	// Pages through the results of an AQL query using offset/limit, yielding one item at a time
+	// Only one page is held in memory, and the caller can stop early by breaking out of the loop
+	// The query's own offset and limit (if any) still apply to the results as a whole
+	// Artifactory doesn't allow paging when properties are included, so properties are fetched separately for each page
+	// If an error occurs, it's yielded with an empty item and iteration stops
+	return func(yield func(Item, error) bool) {
+		if q == nil {
+			yield(Item{}, errors.New("Unable to search for Artifacts without an AQL query."))
+			return
+		}
+		pageLen := pageSize
+		if pageLen <= 0 {
+			pageLen = defaultPageSize
+		}
+
+		paged := q.clone()
+		includeProps := false
+		paged.include = slices.DeleteFunc(paged.include, func(field string) bool {
+			if strings.HasPrefix(field, "property") || strings.HasPrefix(field, "@") {
+				includeProps = true
+				return true
+			}
+			return false
+		})
+		if len(paged.sortFields) == 0 {
+			// Paging needs a stable order
+			paged.SortAsc("repo", "path", "name")
+		}
+
+		offset := q.offset
+		remaining := q.limit
+		for {
+			size := pageLen
+			if q.limit > 0 {
+				if remaining <= 0 {
+					return
+				}
+				size = min(size, remaining)
+			}
+			paged.Offset(offset).Limit(size)
+			common.LogTxtHandler().Debug("Getting page of AQL results at offset " + strconv.Itoa(offset) + "...")
+
+			items, _, err := executeAqlString(paged.String())
+			if err != nil {
+				yield(Item{}, err)
+				return
+			}
+			if includeProps && len(items) != 0 {
+				err = fetchPropsForItems(items)
+				if err != nil {
+					yield(Item{}, err)
+					return
+				}
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(items) < size {
+				return
+			}
+			offset += len(items)
+			remaining -= len(items)
+		}
+	}
+}
+
+func fetchPropsForItems(items []Item) error {
+	// Fills in the properties of each item; see findItems
+	withProps, err := findItems(items, "repo", "path", "name", "property.*")
+	if err != nil {
+		return err
+	}
+	for idx := range items {
+		items[idx].Properties = withProps[itemKey(items[idx])].Properties
+	}
+	return nil
+}
+
+func IterArtifactsByName(artifName string, pageSize int, scope ...SearchScope) iter.Seq2[Item, error] {
+	// Streaming version of GetArtifactsByName; yields every file whose name contains artifName
+	// Unlike GetArtifactsByName, the match is CASE SENSITIVE
+	if artifName == "" {
+		return func(yield func(Item, error) bool) {
+			yield(Item{}, errors.New("Unable to search for Artifact without at least a partial Artifact name."))
+		}
+	}
+	criteria := []Criterion{Eq("type", "file"), Match("name", "*" + artifName + "*")}
+	criteria = append(criteria, getScope(scope).criteria()...)
+	return IterAql(ItemsFind(criteria...), pageSize)
+}
+
+func IterArtifactsByProps(listKvProps []string, pageSize int, scope ...SearchScope) iter.Seq2[Item, error] {
+	// Streaming version of GetArtifactsByProps; yields every file that has ALL of the given properties
+	// Takes 'key=value' or just 'key' (property is set, with any value)
+	if len(listKvProps) == 0 {
+		return func(yield func(Item, error) bool) {
+			yield(Item{}, errors.New("Unable to search by Property without at least one Property Name and, optionally, Value"))
+		}
+	}
+	criteria := []Criterion{Eq("type", "file")}
+	for _, kv := range listKvProps {
+		key, value, hasValue := strings.Cut(kv, "=")
+		if hasValue {
+			criteria = append(criteria, Eq(Prop(key), value))
+		} else {
+			criteria = append(criteria, Match(Prop(key), "*"))
+		}
+	}
+	criteria = append(criteria, getScope(scope).criteria()...)
+	return IterAql(ItemsFind(criteria...), pageSize)
+}
@@ -0,0 +1,159 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// Gavc is a set of Maven coordinates; only Group and Artifact are needed for version searches
+// Ex: search.Gavc{Group: "com.example.tools", Artifact: "image-builder", Version: "1.4.0"}
+type Gavc struct {
+	Group		string
+	Artifact	string
+	Version		string
+	Classifier	string
+}
+
+// MavenVersion is a single version returned by 'GetMavenVersions'
+// Integration is true for snapshot/integration versions (ex: '1.5.0-SNAPSHOT')
+type MavenVersion struct {
+	Version		string
+	Integration	bool
+}
+
+func (g Gavc) String() string {
+	// Coordinates in the usual 'group:artifact:version:classifier' form, leaving off empty trailing parts
+	return strings.TrimRight(g.Group + ":" + g.Artifact + ":" + g.Version + ":" + g.Classifier, ":")
+}
+
+func (g Gavc) queryParams() string {
+	params := url.Values{}
+	if g.Group != "" {
+		params.Set("g", g.Group)
+	}
+	if g.Artifact != "" {
+		params.Set("a", g.Artifact)
+	}
+	if g.Version != "" {
+		params.Set("v", g.Version)
+	}
+	if g.Classifier != "" {
+		params.Set("c", g.Classifier)
+	}
+	return params.Encode()
+}
+
+func GetArtifactsByGavc(coords Gavc, includeProps bool, scope ...SearchScope) ([]Item, error) {
+	// Searches Maven artifacts by any combination of group, artifact, version, and classifier
+	// Only works against repos with a Maven (or similar) layout; the search is CASE SENSITIVE
+	// Properties are only populated if includeProps is true
+	searchScope := getScope(scope)
+	common.LogTxtHandler().Info(">>> Getting Artifacts by GAVC: " + coords.String() + "...")
+
+	if coords == (Gavc{}) {
+		err := errors.New("Unable to search by GAVC without at least one of group, artifact, version, or classifier.")
+		common.LogTxtHandler().Error("Unable to search by GAVC without at least one of group, artifact, version, or classifier.")
+		return nil, err
+	}
+
+	requestPath := util.ServerApi + "/search/gavc?" + coords.queryParams() + searchScope.reposParam()
+	return getItemsWithDetail(requestPath, includeProps, searchScope)
+}
+
+func GetMavenVersions(coords Gavc, scope ...SearchScope) ([]MavenVersion, error) {
+	// Returns the available versions of a Maven artifact; Group and Artifact (FULL names) are required
+	// Setting Version (ex: '1.*') limits the results; only the scope's repos are used, not its path prefix
+	// The search is CASE SENSITIVE
+	searchScope := getScope(scope)
+	common.LogTxtHandler().Info(">>> Getting Versions for: " + coords.String() + "...")
+
+	if coords.Group == "" || coords.Artifact == "" {
+		err := errors.New("Group ID and Artifact Name values can't be empty")
+		common.LogTxtHandler().Error("Supplied group ID is: " + coords.Group + " and artifact name is: " + coords.Artifact)
+		common.LogTxtHandler().Error("Group ID and Artifact Name values can't be empty")
+		return nil, err
+	}
+
+	requestPath := util.ServerApi + "/search/versions?" + coords.queryParams() + searchScope.reposParam()
+	body, status, err := common.SendRequest("GET", requestPath, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		err := errors.New("No version results returned")
+		common.LogTxtHandler().Warn("No version results returned")
+		return nil, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Version search failed with status code " + fmt.Sprint(status))
+		common.LogTxtHandler().Error("Version search failed with status code " + fmt.Sprint(status))
+		return nil, err
+	}
+
+	// JSON return is results with an array of one or more available versions
+	type resultsJson struct {
+		Results []struct {
+			Version		string	`json:"version"`
+			Integration	bool	`json:"integration"`
+		}	`json:"results"`
+	}
+
+	var jsonData resultsJson
+	err = json.Unmarshal(body, &jsonData)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, err
+	}
+	if len(jsonData.Results) == 0 {
+		err := errors.New("No version results returned")
+		common.LogTxtHandler().Warn("No version results returned")
+		return nil, err
+	}
+
+	var versions []MavenVersion
+	for _, r := range jsonData.Results {
+		versions = append(versions, MavenVersion{Version: r.Version, Integration: r.Integration})
+		common.LogTxtHandler().Debug("FOUND VERSION: " + r.Version + " (integration: " + strconv.FormatBool(r.Integration) + ")")
+	}
+	return versions, nil
+}
+
+func GetMavenLatestVersion(coords Gavc, scope ...SearchScope) (string, error) {
+	// Returns the latest version of a Maven artifact, as determined by Artifactory; Group and Artifact (FULL names) are required
+	// Setting Version (ex: '1.*') limits which versions are considered; only the scope's repos are used, not its path prefix
+	// The search is CASE SENSITIVE
+	searchScope := getScope(scope)
+	common.LogTxtHandler().Info(">>> Getting Latest Version for: " + coords.String() + "...")
+
+	if coords.Group == "" || coords.Artifact == "" {
+		err := errors.New("Group ID and Artifact Name values can't be empty")
+		common.LogTxtHandler().Error("Supplied group ID is: " + coords.Group + " and artifact name is: " + coords.Artifact)
+		common.LogTxtHandler().Error("Group ID and Artifact Name values can't be empty")
+		return "", err
+	}
+
+	requestPath := util.ServerApi + "/search/latestVersion?" + coords.queryParams() + searchScope.reposParam()
+	body, status, err := common.SendRequest("GET", requestPath, nil, "")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		err := errors.New("No version results returned")
+		common.LogTxtHandler().Warn("No version results returned (status code " + fmt.Sprint(status) + ")")
+		return "", err
+	}
+
+	// The latest version is returned as plain text rather than JSON
+	latestVersion := strings.TrimSpace(string(body))
+	common.LogTxtHandler().Info("LATEST VERSION: " + latestVersion)
+	return latestVersion, nil
+}
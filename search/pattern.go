@@ -0,0 +1,123 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+type ArtifactPath struct {
+	Repo	string
+	Path	string	// Folder path relative to the repo; '.' for the root of the repo
+	Name	string
+}
+
+func (a ArtifactPath) Uri() string {
+	// Artifact URI (http://server.com:8081/artifactory/api/storage/repo-key/folder/artifact.ext)
+	return common.SetArtifUriFromRepoPathName("", a.Repo, a.Path, a.Name)
+}
+
+func (a ArtifactPath) String() string {
+	// Artifactory path of the artifact (/repo-key/folder/artifact.ext)
+	if a.Path == "" || a.Path == "." {
+		return "/" + a.Repo + "/" + a.Name
+	}
+	return "/" + a.Repo + "/" + a.Path + "/" + a.Name
+}
+
+func NewArtifactPath(repo, artifPath string) ArtifactPath {
+	// Splits a path within a repo ('folder/artifact.ext') into an ArtifactPath
+	folder, name := path.Split(strings.Trim(artifPath, "/"))
+	folder = strings.TrimSuffix(folder, "/")
+	if folder == "" {
+		folder = "."
+	}
+	return ArtifactPath{Repo: repo, Path: folder, Name: name}
+}
+
+func GetArtifactsByPattern(pattern string) ([]ArtifactPath, error) {
+	// Searches for artifacts matching a path glob in the format of 'repo-key:path/pattern' (ex: 'images-local:windows/**/win2022-*/*.ovf')
+	// '*' matches within a single folder/name, '?' matches one character, and '**' matches any number of folders
+	// The repo key can't contain wildcards; the search is CASE SENSITIVE
+	var results []ArtifactPath
+
+	common.LogTxtHandler().Info(">>> Getting Artifacts by Pattern: " + pattern + "...")
+
+	repo, filePattern, found := strings.Cut(pattern, ":")
+	if !found || repo == "" || filePattern == "" {
+		err := errors.New("Pattern must be in the format of 'repo-key:path/pattern'.")
+		common.LogTxtHandler().Error("Pattern must be in the format of 'repo-key:path/pattern'.")
+		return nil, err
+	}
+	if strings.ContainsAny(repo, "*?") {
+		err := errors.New("The repo key in a pattern can't contain wildcards.")
+		common.LogTxtHandler().Error("The repo key in a pattern can't contain wildcards.")
+		return nil, err
+	}
+
+	requestPath := util.ServerApi + "/search/pattern?pattern=" + url.QueryEscape(repo + ":" + strings.TrimPrefix(filePattern, "/"))
+	body, status, err := common.SendRequest("GET", requestPath, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		err := errors.New("Repo '" + repo + "' not found.")
+		common.LogTxtHandler().Error("Repo '" + repo + "' not found.")
+		return nil, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Pattern search failed with status code " + fmt.Sprint(status))
+		common.LogTxtHandler().Error("Pattern search failed with status code " + fmt.Sprint(status))
+		return nil, err
+	}
+
+	// JSON return is the repo URI and the matching files' paths relative to the repo
+	type resultsJson struct {
+		RepoUri			string		`json:"repoUri"`
+		SourcePattern	string		`json:"sourcePattern"`
+		Files			[]string	`json:"files"`
+	}
+
+	var jsonData *resultsJson
+	err = json.Unmarshal(body, &jsonData)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, err
+	}
+	if jsonData == nil {
+		return results, nil
+	}
+
+	for _, file := range jsonData.Files {
+		artifact := NewArtifactPath(repo, file)
+		results = append(results, artifact)
+		common.LogTxtHandler().Debug("FOUND ARTIFACT: " + artifact.String())
+	}
+	common.LogTxtHandler().Info("Found " + strconv.Itoa(len(results)) + " artifact(s).")
+	return results, nil
+}
+
+func FilterListByPattern(pattern string, listArtifUris []string) []string {
+	// Filters a list of artifact URIs with the same path glob syntax as 'GetArtifactsByPattern', but client-side
+	// Both 'repo-key:path/pattern' and 'repo-key/path/pattern' are accepted
+	var filteredList []string
+	match := common.GlobToRegexp(pattern)
+
+	for _, artifUri := range listArtifUris {
+		repo, itemPath, name := common.ParseArtifUriForRepoPathName("", artifUri)
+		artifPath := ArtifactPath{Repo: repo, Path: itemPath, Name: name}.String()
+		if match.MatchString(strings.TrimPrefix(artifPath, "/")) {
+			filteredList = append(filteredList, artifUri)
+		}
+	}
+	return filteredList
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/raynaluzier/artifactory-go-sdk/common"
@@ -16,9 +17,66 @@ import (
 var request *http.Request
 var err error
 
-func GetArtifactsByProps(listKvProps []string) ([]string, error) {
+// ErrNoResults is returned by searches that find nothing
+var ErrNoResults = errors.New("No results returned")
+
+// SearchScope limits a search to certain repos and, optionally, a path within them
+// PathPrefix is relative to the repo (ex: 'windows/2022') and matches that folder and everything under it
+type SearchScope struct {
+	Repos			[]string
+	PathPrefix		string
+}
+
+func getScope(scope []SearchScope) SearchScope {
+	if len(scope) != 0 {
+		return scope[0]
+	}
+	return SearchScope{}
+}
+
+func (s SearchScope) reposParam() string {
+	// Repos are filtered server-side with the 'repos' query parameter
+	if len(s.Repos) == 0 {
+		return ""
+	}
+	return "&repos=" + strings.Join(s.Repos, ",")
+}
+
+func (s SearchScope) InScope(artifUri string) bool {
+	// Checks an artifact URI against the scope's repos and path prefix
+	repo, itemPath, _ := common.ParseArtifUriForRepoPathName("", artifUri)
+	if len(s.Repos) != 0 && !slices.Contains(s.Repos, repo) {
+		return false
+	}
+	prefix := strings.Trim(s.PathPrefix, "/")
+	if prefix != "" && itemPath != prefix && !strings.HasPrefix(itemPath, prefix + "/") {
+		return false
+	}
+	return true
+}
+
+func (s SearchScope) criteria() []Criterion {
+	// Same scope expressed as AQL criteria
+	var criteria []Criterion
+	if len(s.Repos) != 0 {
+		var repos []Criterion
+		for _, r := range s.Repos {
+			repos = append(repos, Eq("repo", r))
+		}
+		criteria = append(criteria, Or(repos...))
+	}
+	if prefix := strings.Trim(s.PathPrefix, "/"); prefix != "" {
+		criteria = append(criteria, Or(Eq("path", prefix), Match("path", prefix + "/*")))
+	}
+	return criteria
+}
+
+func GetArtifactsByProps(listKvProps []string, scope ...SearchScope) ([]string, error) {
 	// Takes in list of property key/values strings (ex: 'release=latest-stable', 'testing=passed')
+	// Optionally takes a scope to limit the search to certain repos and a path within them
 	var strKvProps string
+	searchScope := getScope(scope)
+	strScope := searchScope.reposParam()
 	listArtifUris := []string{}
 	bearer := common.SetBearer(util.Token)
 	requestPath := util.ServerApi + "/search/prop?"
@@ -29,17 +87,17 @@ func GetArtifactsByProps(listKvProps []string) ([]string, error) {
 		if len(listKvProps) > 1 {
 			// If there's more than one prop name/value supplied, adds the required '&' separater between them
 			strKvProps = strings.Join(listKvProps, "&")
-			request, err = http.NewRequest("GET", requestPath + strKvProps, nil)
-			common.LogTxtHandler().Debug("REQUEST: Sending 'GET' request to: " + requestPath + strKvProps)
+			request, err = http.NewRequest("GET", requestPath + strKvProps + strScope, nil)
+			common.LogTxtHandler().Debug("REQUEST: Sending 'GET' request to: " + requestPath + strKvProps + strScope)
 
 		} else {
-			request, err = http.NewRequest("GET", requestPath + listKvProps[0], nil)
-			common.LogTxtHandler().Debug("REQUEST: Sending 'GET' request to: " + requestPath + listKvProps[0])
+			request, err = http.NewRequest("GET", requestPath + listKvProps[0] + strScope, nil)
+			common.LogTxtHandler().Debug("REQUEST: Sending 'GET' request to: " + requestPath + listKvProps[0] + strScope)
 		}
 
 		request.Header.Add("Authorization", bearer)
 	
-		client := &http.Client{}
+		client := common.NewClient()
 		response, err := client.Do(request)
 		if err != nil {
 			strErr := fmt.Sprintf("%v\n", err)
@@ -70,9 +128,17 @@ func GetArtifactsByProps(listKvProps []string) ([]string, error) {
 			if len(jsonData.Results) != 0 {
 				for idx, r := range jsonData.Results {
 					r = jsonData.Results[idx]
+					if !searchScope.InScope(r.Uri) {
+						continue
+					}
 					listArtifUris = append(listArtifUris, r.Uri)
 					common.LogTxtHandler().Info("FOUND ARTIFACT: " + r.Uri)
 				}
+				if len(listArtifUris) == 0 {
+					err := errors.New("No artifacts returned within the search scope.")
+					common.LogTxtHandler().Warn("No artifacts returned within the search scope.")
+					return nil, err
+				}
 				return listArtifUris, nil
 			} else {
 				err := errors.New("No artifacts returned.")
@@ -96,11 +162,13 @@ func GetArtifactsByProps(listKvProps []string) ([]string, error) {
 	return listArtifUris, nil
 }
 
-func GetArtifactsByName(artifName string) ([]string, error) {
+func GetArtifactsByName(artifName string, scope ...SearchScope) ([]string, error) {
 	// Searches for artifacts by artifact name (can be partial)
+	// Optionally takes a scope to limit the search to certain repos and a path within them
 	listArtifUris := []string{}
 	bearer := common.SetBearer(util.Token)
-	requestPath := util.ServerApi + "/search/artifact?name=" + artifName
+	searchScope := getScope(scope)
+	requestPath := util.ServerApi + "/search/artifact?name=" + artifName + searchScope.reposParam()
 
 	common.LogTxtHandler().Info(">>> Getting Artifacts by Name...")
 
@@ -109,7 +177,7 @@ func GetArtifactsByName(artifName string) ([]string, error) {
 		request, err = http.NewRequest("GET", requestPath, nil)
 		request.Header.Add("Authorization", bearer)
 
-		client := &http.Client{}
+		client := common.NewClient()
 		response, err := client.Do(request)
 		if err != nil {
 			strErr := fmt.Sprintf("%v\n", err)
@@ -141,12 +209,20 @@ func GetArtifactsByName(artifName string) ([]string, error) {
 			if len(jsonData.Results) != 0 {
 				for idx, r := range jsonData.Results {
 					r = jsonData.Results[idx]
+					if !searchScope.InScope(r.Uri) {
+						continue
+					}
 					listArtifUris = append(listArtifUris, r.Uri)
 					common.LogTxtHandler().Info("FOUND ARTIFACT: " + r.Uri)
 				}
+				if len(listArtifUris) == 0 {
+					err := errors.New("No artifacts returned within the search scope.")
+					common.LogTxtHandler().Warn("No artifacts returned within the search scope.")
+					return nil, err
+				}
 				return listArtifUris, nil
 			} else {
-				err := errors.New("No results returned")
+				err := ErrNoResults
 				common.LogTxtHandler().Warn("No results returned")
 				return nil, err
 			}
@@ -192,4 +268,121 @@ func FilterListByFileType(ext string, listArtifacts []string) ([]string, error)
 		return nil, err
 	}
 	return filteredList, err
-}
\ No newline at end of file
+}
+func GetArtifactsByAql(query string) ([]string, error) {
+	// Runs a raw AQL 'items.find(...)' query and returns the artifact URIs of the matching items
+	// The query must return the 'repo', 'path', and 'name' fields (the default for items.find)
+	// For a typed query builder, see ItemsFind and ExecuteAql
+	listArtifUris := []string{}
+
+	common.LogTxtHandler().Info(">>> Getting Artifacts by AQL Query...")
+
+	if strings.TrimSpace(query) == "" {
+		err := errors.New("Unable to search for Artifacts without an AQL query.")
+		common.LogTxtHandler().Error("Unable to search for Artifacts without an AQL query.")
+		return nil, err
+	}
+
+	items, _, err := executeAqlString(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) != 0 {
+		for _, item := range items {
+			listArtifUris = append(listArtifUris, item.Uri())
+			common.LogTxtHandler().Info("FOUND ARTIFACT: " + item.Uri())
+		}
+		return listArtifUris, nil
+	} else {
+		err := ErrNoResults
+		common.LogTxtHandler().Warn("No results returned")
+		return nil, err
+	}
+}
+
+func GetArtifactsByChecksum(checksumType, checksum string, scope ...SearchScope) ([]string, error) {
+	// Searches for artifacts by checksum; checksumType is 'sha1', 'sha256', or 'md5'
+	// Optionally takes a scope to limit the search to certain repos and a path within them
+	listArtifUris := []string{}
+	searchScope := getScope(scope)
+	checksumType = common.ConvertToLowercase(checksumType)
+
+	common.LogTxtHandler().Info(">>> Getting Artifacts by Checksum...")
+
+	if checksumType != "sha1" && checksumType != "sha256" && checksumType != "md5" {
+		err := errors.New("Unsupported checksum type '" + checksumType + "'. Supported types are 'sha1', 'sha256', and 'md5'.")
+		common.LogTxtHandler().Error("Unsupported checksum type '" + checksumType + "'. Supported types are 'sha1', 'sha256', and 'md5'.")
+		return nil, err
+	}
+	if checksum == "" {
+		err := errors.New("Unable to search for Artifacts without a checksum.")
+		common.LogTxtHandler().Error("Unable to search for Artifacts without a checksum.")
+		return nil, err
+	}
+
+	requestPath := util.ServerApi + "/search/checksum?" + checksumType + "=" + checksum + searchScope.reposParam()
+	body, status, err := common.SendRequest("GET", requestPath, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Checksum search failed with status code " + fmt.Sprint(status))
+		common.LogTxtHandler().Error("Checksum search failed with status code " + fmt.Sprint(status))
+		return nil, err
+	}
+
+	// JSON return is results with an array of one or more URI strings
+	type resultsJson struct {
+		Results []struct{
+			Uri string `json:"uri"`
+		} `json:"results"`
+	}
+
+	var jsonData *resultsJson
+	err = json.Unmarshal(body, &jsonData)
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Could not unmarshal response - " + strErr)
+		return nil, err
+	}
+
+	for _, r := range jsonData.Results {
+		if !searchScope.InScope(r.Uri) {
+			continue
+		}
+		listArtifUris = append(listArtifUris, r.Uri)
+		common.LogTxtHandler().Info("FOUND ARTIFACT: " + r.Uri)
+	}
+	if len(listArtifUris) == 0 {
+		err := ErrNoResults
+		common.LogTxtHandler().Warn("No results returned")
+		return nil, err
+	}
+	return listArtifUris, nil
+}
+
+func GetArtifactsByLocalFile(filePath string, scope ...SearchScope) ([]string, error) {
+	// Hashes a local file (ex: an OVA or VMDK) and returns the artifacts in Artifactory with identical content
+	// Searches by SHA256 first; artifacts uploaded before Artifactory indexed SHA256 may only be found by SHA1
+	common.LogTxtHandler().Info(">>> Getting Artifacts Matching Local File: " + filePath + "...")
+
+	if filePath == "" {
+		err := errors.New("Unable to search for Artifacts without the path to a local file.")
+		common.LogTxtHandler().Error("Unable to search for Artifacts without the path to a local file.")
+		return nil, err
+	}
+
+	sha1, _, sha256, err := common.GetFileChecksums(filePath)
+	if err != nil {
+		return nil, err
+	}
+	common.LogTxtHandler().Debug("SHA256: " + sha256 + ", SHA1: " + sha1)
+
+	listArtifUris, err := GetArtifactsByChecksum("sha256", sha256, scope...)
+	if err != nil || len(listArtifUris) == 0 {
+		common.LogTxtHandler().Debug("No SHA256 matches; searching by SHA1...")
+		listArtifUris, err = GetArtifactsByChecksum("sha1", sha1, scope...)
+	}
+	return listArtifUris, err
+}
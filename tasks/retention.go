@@ -0,0 +1,379 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raynaluzier/artifactory-go-sdk/common"
+	"github.com/raynaluzier/artifactory-go-sdk/operations"
+	"github.com/raynaluzier/artifactory-go-sdk/search"
+	"github.com/raynaluzier/artifactory-go-sdk/util"
+)
+
+// Retention rule types
+const (
+	KeepLatest		= "keep-latest"		// Keeps the latest 'Count' images (by created date) for each value of 'Property'; all images if 'Property' is empty
+	KeepWithProp	= "keep-prop"		// Keeps images that have 'Property' ('key' or 'key=value')
+	DeleteUnused	= "delete-unused"	// Deletes images created more than 'Age' ago that haven't been downloaded within 'Age' (including never)
+)
+
+// Retention actions
+const (
+	RetainKeep		= "keep"
+	RetainDelete	= "delete"
+)
+
+// RetentionRule is a single rule of a retention policy; which fields are used depends on the Type
+type RetentionRule struct {
+	Type		string
+	Property	string
+	Count		int
+	Age			time.Duration
+}
+
+// RetentionPolicy is a set of rules evaluated against the images in a repo, or a path within it
+// An image is a folder holding a main image file (one of Exts; defaults to ova, ovf, and vmtx), and is kept or deleted as a whole
+// Keep rules always win. Images no keep rule protects are deleted only if a delete rule matches them; a policy with no delete rules deletes nothing
+type RetentionPolicy struct {
+	Repo		string
+	Path		string
+	Exts		[]string
+	Rules		[]RetentionRule
+}
+
+// RetentionDecision is the planned (and once applied, actual) outcome for one image
+// Folder is the Artifactory path of the image folder ('/repo-key/folder/image'); Files are the artifact URIs of its files
+// Status is one of "Planned" (will be deleted), "Skipped" (kept), "Success", or "Failed"
+type RetentionDecision struct {
+	Folder		string
+	Image		string
+	Files		[]string
+	Size		int64
+	Created		time.Time
+	Action		string
+	Reasons		[]string
+	Status		string
+	Err			error
+}
+
+type retentionImage struct {
+	decision	RetentionDecision
+	props		map[string][]string
+	keep		[]string
+	notKept		[]string
+	del			[]string
+}
+
+func PlanRetention(serverApi, token string, policy RetentionPolicy) ([]RetentionDecision, error) {
+	// Evaluates the policy's rules against the images in the repo/path and returns a decision, with reasons, for each image
+	// Nothing is changed; pass the plan to ApplyRetention to carry it out
+	util.ServerApi = serverApi
+	util.Token     = token
+
+	common.LogTxtHandler().Info(">>> PLANNING IMAGE RETENTION...")
+	err := checkRetentionPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := getRetentionImages(policy)
+	if err != nil {
+		return nil, err
+	}
+	common.LogTxtHandler().Info("Images found: " + strconv.Itoa(len(images)))
+
+	hasDeleteRule := false
+	for _, rule := range policy.Rules {
+		switch rule.Type {
+		case KeepLatest:
+			applyKeepLatest(images, rule)
+		case KeepWithProp:
+			for _, image := range images {
+				key, value, hasValue := strings.Cut(rule.Property, "=")
+				if values, found := image.props[key]; found && (!hasValue || slices.Contains(values, value)) {
+					image.keep = append(image.keep, "has property '" + rule.Property + "'")
+				}
+			}
+		case DeleteUnused:
+			hasDeleteRule = true
+			err := applyDeleteUnused(images, rule, policy)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var plan []RetentionDecision
+	for _, image := range images {
+		d := image.decision
+		switch {
+		case len(image.keep) != 0:
+			d.Action, d.Status, d.Reasons = RetainKeep, "Skipped", image.keep
+		case !hasDeleteRule:
+			d.Action, d.Status = RetainKeep, "Skipped"
+			d.Reasons = append([]string{"policy has no delete rule"}, image.notKept...)
+		case len(image.del) != 0:
+			d.Action, d.Status = RetainDelete, "Planned"
+			d.Reasons = append(image.del, image.notKept...)
+		default:
+			d.Action, d.Status, d.Reasons = RetainKeep, "Skipped", []string{"no delete rule matched"}
+		}
+		plan = append(plan, d)
+	}
+
+	// Deleting a folder deletes everything under it, so an image folder holding a kept image can't be deleted
+	for idx := range plan {
+		if plan[idx].Action != RetainDelete {
+			continue
+		}
+		for _, other := range plan {
+			if other.Action == RetainKeep && strings.HasPrefix(other.Folder, plan[idx].Folder + "/") {
+				plan[idx].Action, plan[idx].Status = RetainKeep, "Skipped"
+				plan[idx].Reasons = []string{"folder contains kept image '" + other.Folder + "'"}
+				break
+			}
+		}
+	}
+
+	toDelete := 0
+	for _, d := range plan {
+		if d.Action == RetainDelete {
+			toDelete++
+			common.LogTxtHandler().Info("DELETE: " + d.Folder + " - " + strings.Join(d.Reasons, "; "))
+		} else {
+			common.LogTxtHandler().Debug("KEEP: " + d.Folder + " - " + strings.Join(d.Reasons, "; "))
+		}
+	}
+	common.LogTxtHandler().Info("Images to delete: " + strconv.Itoa(toDelete) + " of " + strconv.Itoa(len(plan)))
+	return plan, nil
+}
+
+func ApplyRetention(serverApi, token string, plan []RetentionDecision, execute bool) ([]RetentionDecision, error) {
+	// Deletes the image folders the plan marks for deletion; only runs when 'execute' is true, otherwise it's a dry run
+	// and the plan is returned as is. Folders are deleted with at most util.MaxWorkers requests in flight; a failure on one
+	// image doesn't stop the others
+	util.ServerApi = serverApi
+	util.Token     = token
+	results := slices.Clone(plan)
+
+	common.LogTxtHandler().Info(">>> APPLYING IMAGE RETENTION...")
+	if !execute {
+		common.LogTxtHandler().Info("Dry run; no images will be deleted.")
+		return results, nil
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(util.MaxWorkers, 1))
+	for idx := range results {
+		if results[idx].Action != RetainDelete {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_, err := operations.DeleteItem(results[idx].Folder)
+			if err != nil {
+				results[idx].Status, results[idx].Err = "Failed", err
+				return
+			}
+			results[idx].Status = "Success"
+		}(idx)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, d := range results {
+		if d.Status == "Failed" {
+			failed++
+		}
+	}
+	if failed != 0 {
+		common.LogTxtHandler().Warn("Unable to delete " + strconv.Itoa(failed) + " image(s).")
+	}
+	return results, nil
+}
+
+func RunRetention(serverApi, token string, policy RetentionPolicy, execute bool) ([]RetentionDecision, error) {
+	// Plans and applies the policy in one call; a dry run unless 'execute' is true
+	plan, err := PlanRetention(serverApi, token, policy)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyRetention(serverApi, token, plan, execute)
+}
+
+func checkRetentionPolicy(policy RetentionPolicy) error {
+	if policy.Repo == "" {
+		err := errors.New("Unable to plan retention without a repo.")
+		common.LogTxtHandler().Error("Unable to plan retention without a repo.")
+		return err
+	}
+	if len(policy.Rules) == 0 {
+		err := errors.New("Unable to plan retention without any rules.")
+		common.LogTxtHandler().Error("Unable to plan retention without any rules.")
+		return err
+	}
+	for _, rule := range policy.Rules {
+		var problem string
+		switch rule.Type {
+		case KeepLatest:
+			if rule.Count <= 0 {
+				problem = "Rule '" + KeepLatest + "' requires a count greater than 0."
+			}
+		case KeepWithProp:
+			if rule.Property == "" {
+				problem = "Rule '" + KeepWithProp + "' requires a property."
+			}
+		case DeleteUnused:
+			if rule.Age <= 0 {
+				problem = "Rule '" + DeleteUnused + "' requires an age greater than 0."
+			}
+		default:
+			problem = "Unknown retention rule type: '" + rule.Type + "'."
+		}
+		if problem != "" {
+			common.LogTxtHandler().Error(problem)
+			return errors.New(problem)
+		}
+	}
+	return nil
+}
+
+func getRetentionImages(policy RetentionPolicy) ([]*retentionImage, error) {
+	// Finds the image folders in the repo/path, along with their files and the properties and created date of the main image file
+	exts := policy.Exts
+	if len(exts) == 0 {
+		exts = []string{"ova", "ovf", "vmtx"}
+	}
+
+	criteria := []search.Criterion{search.Eq("repo", policy.Repo), search.Eq("type", "file")}
+	folder := strings.Trim(policy.Path, "/")
+	if folder != "" {
+		criteria = append(criteria, search.Or(search.Eq("path", folder), search.Match("path", folder + "/*")))
+	}
+	q := search.ItemsFind(criteria...).Include("repo", "path", "name", "type", "size", "created", "property.*")
+
+	byFolder := make(map[string]*retentionImage)
+	mainFiles := make(map[string]search.Item)
+	for item, err := range search.IterAql(q, 0) {
+		if err != nil {
+			return nil, err
+		}
+		image, found := byFolder[item.Path]
+		if !found {
+			image = &retentionImage{decision: RetentionDecision{Folder: "/" + item.Repo + "/" + item.Path}}
+			byFolder[item.Path] = image
+		}
+		image.decision.Files = append(image.decision.Files, item.Uri())
+		image.decision.Size += item.Size
+
+		ext := strings.ToLower(strings.TrimPrefix(path.Ext(item.Name), "."))
+		if slices.ContainsFunc(exts, func(e string) bool { return strings.EqualFold(strings.TrimPrefix(e, "."), ext) }) {
+			// If a folder holds more than one main file, the newest one stands for the image
+			if current, found := mainFiles[item.Path]; !found || item.Created.After(current.Created) {
+				mainFiles[item.Path] = item
+			}
+		}
+	}
+
+	var images []*retentionImage
+	for folderPath, image := range byFolder {
+		main, found := mainFiles[folderPath]
+		if !found {
+			continue
+		}
+		if folderPath == "." {
+			common.LogTxtHandler().Warn("Skipping image at the root of the repo; only images in folders can be deleted: " + main.Name)
+			continue
+		}
+		image.decision.Image = main.Name
+		image.decision.Created = main.Created
+		image.props = main.Properties
+		images = append(images, image)
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].decision.Folder < images[j].decision.Folder })
+	return images, nil
+}
+
+func applyKeepLatest(images []*retentionImage, rule RetentionRule) {
+	// Groups the images by the rule's property value, then keeps the newest 'Count' in each group
+	groups := make(map[string][]*retentionImage)
+	for _, image := range images {
+		group := ""
+		if rule.Property != "" {
+			values := slices.Clone(image.props[rule.Property])
+			sort.Strings(values)
+			group = rule.Property + "=" + strings.Join(values, ",")
+		}
+		groups[group] = append(groups[group], image)
+	}
+
+	for group, members := range groups {
+		sort.SliceStable(members, func(i, j int) bool { return members[i].decision.Created.After(members[j].decision.Created) })
+		label := "the latest " + strconv.Itoa(rule.Count)
+		if group != "" {
+			label = label + " for '" + group + "'"
+		}
+		for idx, image := range members {
+			if idx < rule.Count {
+				image.keep = append(image.keep, "one of " + label)
+			} else {
+				image.notKept = append(image.notKept, "not one of " + label)
+			}
+		}
+	}
+}
+
+func applyDeleteUnused(images []*retentionImage, rule RetentionRule, policy RetentionPolicy) error {
+	// An image is unused if none of its files were downloaded since the cutoff, and it was created before the cutoff
+	cutoff := time.Now().Add(-rule.Age)
+	scope := search.SearchScope{Repos: []string{policy.Repo}, PathPrefix: policy.Path}
+
+	unused, err := search.GetArtifactsNotDownloadedSince(cutoff, cutoff, scope)
+	if errors.Is(err, search.ErrNoResults) {
+		return nil
+	}
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error getting unused artifacts - " + strErr)
+		return err
+	}
+	// Compared by repo/path/name, as the usage API's URIs may not start with the same base as util.ServerApi
+	unusedItems := make(map[string]bool)
+	for _, u := range unused {
+		unusedItems[retentionItemKey(u.Uri)] = true
+	}
+
+	for _, image := range images {
+		if !image.decision.Created.Before(cutoff) {
+			continue
+		}
+		used := slices.ContainsFunc(image.decision.Files, func(uri string) bool { return !unusedItems[retentionItemKey(uri)] })
+		if !used {
+			image.del = append(image.del, "created more than " + formatAge(rule.Age) + " ago and not downloaded since")
+		}
+	}
+	return nil
+}
+
+func retentionItemKey(artifUri string) string {
+	repo, itemPath, name := common.ParseArtifUriForRepoPathName("", artifUri)
+	return repo + "/" + itemPath + "/" + name
+}
+
+func formatAge(age time.Duration) string {
+	// Whole days read better than hours in reasons (ex: '30 days' rather than '720h0m0s')
+	if age % (24 * time.Hour) == 0 {
+		return strconv.Itoa(int(age / (24 * time.Hour))) + " days"
+	}
+	return age.String()
+}
@@ -1,11 +1,16 @@
 package tasks
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/raynaluzier/artifactory-go-sdk/common"
 	"github.com/raynaluzier/artifactory-go-sdk/operations"
@@ -13,7 +18,8 @@ import (
 	"github.com/raynaluzier/artifactory-go-sdk/util"
 )
 
-func GetImageDetails(serverApi, token, artifName, ext string, kvProps []string) (string, string, string, string, error) {
+func GetImageDetails(serverApi, token, artifName, ext string, kvProps []string, scope ...search.SearchScope) (string, string, string, string, error) {
+	// Optionally takes a scope to limit the search to certain repos and a path within them
 	util.ServerApi = serverApi
 	util.Token     = token
 	var artifactUri string
@@ -21,7 +27,7 @@ func GetImageDetails(serverApi, token, artifName, ext string, kvProps []string)
 
 	common.LogTxtHandler().Debug(">>> GETTING IMAGE DETAILS...")
 	common.LogTxtHandler().Debug("Getting artifacts by name...")
-	listArtifacts, err := search.GetArtifactsByName(artifName)
+	listArtifacts, err := search.GetArtifactsByName(artifName, scope...)
 	if err != nil {
 		strErr = fmt.Sprintf("%v\n", err)
 		common.LogTxtHandler().Error("Error getting list of matching artifacts - " + strErr)
@@ -166,6 +172,10 @@ func UploadGeneralArtifact(serverApi, token, sourcePath, artifPath, fileName str
 
 	common.LogTxtHandler().Debug("Source Path: " + sourcePath)
 	common.LogTxtHandler().Debug("Artifact Path: " + artifPath)
+	err := checkUploadSchema(artifPath + fileName, nil)
+	if err != nil {
+		return "Failed", err
+	}
 	
 	result, err := operations.CheckFileAndUpload(sourcePath, artifPath, fileName, "")
 	
@@ -213,6 +223,19 @@ func UploadArtifacts(serverApi, token, imageType, imageName, sourceDir, targetDi
 
 	// sourceDir ex: c:\\lab\\image_name or /lab/image_name - We'll check for/add ending slash if needed
 	// targetDir ex: /repo-name/folder - We'll check for/add ending slash if needed
+	// Nothing is uploaded if the target is covered by a property schema with required properties; use UploadArtifactsWithProps
+	if imageName != "" && sourceDir != "" && targetDir != "" {
+		artifPath := common.CheckAddSlashToPath(targetDir) + imageName + "/" + imageName + "." + strings.ToLower(imageType)
+		err := checkUploadSchema(artifPath, nil)
+		if err != nil {
+			return "Properties do not conform to schema"
+		}
+	}
+	return uploadArtifacts(serverApi, token, imageType, imageName, sourceDir, targetDir)
+}
+
+func uploadArtifacts(serverApi, token, imageType, imageName, sourceDir, targetDir string) (string) {
+	// Callers check the target against any property schema before calling this
 	util.ServerApi = serverApi
 	util.Token	   = token
 	var fileName string
@@ -597,6 +620,69 @@ func UploadArtifacts(serverApi, token, imageType, imageName, sourceDir, targetDi
 	}
 }
 
+func checkUploadSchema(artifPath string, kvProps []string) error {
+	// Uploads into a path covered by a property schema must carry every property it requires, so artifacts can't be
+	// added without them; the properties given are set once the upload is done (see UploadArtifactsWithProps)
+	err := operations.ValidateProps(artifPath, kvProps, true)
+	if err != nil {
+		strErr := fmt.Sprintf("%v", err)
+		common.LogTxtHandler().Error("Properties do not conform to schema - " + strErr)
+		if len(kvProps) == 0 {
+			common.LogTxtHandler().Error("Upload with UploadArtifactsWithProps to set the properties the schema requires.")
+		}
+		return err
+	}
+	return nil
+}
+
+func UploadArtifactsWithProps(serverApi, token, imageType, imageName, sourceDir, targetDir string, kvProps []string) (string, error) {
+	// Same as UploadArtifacts, but also sets the given properties on the image's main file (.ova, .ovf, or .vmtx)
+	// The properties are checked BEFORE anything is uploaded: keys can't contain special characters, and the properties
+	// must conform to any schema registered for the target path, including having all required properties
+	// Values may contain any characters (ex: RFC3339 dates, '/'); they're escaped when set
+	util.ServerApi = serverApi
+	util.Token	   = token
+
+	if imageName == "" || targetDir == "" {
+		common.LogTxtHandler().Error("One or more required inputs have not been provided.")
+		return "Missing required inputs", errors.New("Missing required inputs")
+	}
+
+	props := make(map[string][]string)
+	for _, kv := range kvProps {
+		key, value, _ := strings.Cut(kv, "=")
+		if key == "" || common.ContainsSpecialChars([]string{key}) {
+			err := errors.New("Property '" + key + "' must have a name without special characters.")
+			common.LogTxtHandler().Error("Property '" + key + "' must have a name without special characters.")
+			return "Invalid property", err
+		}
+		props[key] = append(props[key], value)
+	}
+
+	artifPath := common.CheckAddSlashToPath(targetDir) + imageName + "/" + imageName + "." + strings.ToLower(imageType)
+	common.LogTxtHandler().Debug("Validating properties for: " + artifPath)
+	err := checkUploadSchema(artifPath, kvProps)
+	if err != nil {
+		return "Properties do not conform to schema", err
+	}
+
+	result := uploadArtifacts(serverApi, token, imageType, imageName, sourceDir, targetDir)
+	if result != "End of upload process" {
+		return result, errors.New(result)
+	}
+
+	if len(props) != 0 {
+		artifUri := util.ServerApi + "/storage" + artifPath
+		_, err := operations.SetArtifactPropValues(artifUri, props)
+		if err != nil {
+			strErr := fmt.Sprintf("%v", err)
+			common.LogTxtHandler().Error("Unable to set artifact properties - " + strErr)
+			return "Uploaded, but unable to set properties", err
+		}
+	}
+	return result, nil
+}
+
 func SetProps(serverApi, token, artifUri string, kvProps []string) (string, error) {
 	util.ServerApi = serverApi
 	util.Token	   = token
@@ -636,13 +722,8 @@ func DownloadArtifacts(serverApi, token, downloadUri, outputDir string) string {
 	
 	common.LogTxtHandler().Info("DOWNLOADING ARTIFACT(S) FROM ARTIFACTORY...")
 	
-	var artifactPath string
-	var downloadList []string
 	var resultMsg string
 	var err error
-	var strI string
-	var extString string
-	var task string
 
 	if downloadUri != "" && outputDir != "" {
 		downloadUri   = strings.ToLower(downloadUri)
@@ -675,152 +756,609 @@ func DownloadArtifacts(serverApi, token, downloadUri, outputDir string) string {
 			}
 		}
 		
-		if ext == ".ova" {
-			common.LogTxtHandler().Info("Image type identified as OVA. Downloading OVA file...")
-			resultMsg, err = operations.RetrieveArtifact(downloadUri)
+		// The image's files are laid out the same way as for DeleteImage; required files are downloaded first, and if any
+		// fail, the optional (ex: disk) files aren't checked for
+		required, _ := imageLayoutFiles(imageName, ext, func(name string) bool { return false })
+		if len(required) == 0 {
+			common.LogTxtHandler().Error("Image type not recognized: " + ext)
+			return "End of download process"
+		}
+
+		common.LogTxtHandler().Info("Image type identified as " + strings.ToUpper(strings.TrimPrefix(ext, ".")) + ". Downloading image files...")
+		for _, name := range required {
+			common.LogTxtHandler().Info("Downloading: " + downloadPath + name)
+			resultMsg, err = operations.RetrieveArtifact(downloadPath + name)
 			if err != nil {
+				common.LogTxtHandler().Error("Error downloading " + downloadPath + name)
 				common.LogTxtHandler().Error(resultMsg) // Will contain "Error" with additional info
+				common.LogTxtHandler().Error("Errors encountered. The remainder of the file download process will terminate.")
+				return "File download failed"
 			}
-
-		} else if ext == ".ovf" {
-			// Download OVF and assoc files
-			common.LogTxtHandler().Info("Image type identified as OVF. Downloading OVF files...")
-			downloadList = []string{".ovf", ".mf"}
-			for _, item := range downloadList {
-				artifactPath = downloadPath + imageName + item // builds URI path for each expected file type
-				common.LogTxtHandler().Info("Downloading: " + artifactPath)
-				resultMsg, err = operations.RetrieveArtifact(artifactPath)
-				if err != nil {
-					common.LogTxtHandler().Error("Error downloading " + artifactPath)
-					common.LogTxtHandler().Error(resultMsg)
-					break
-				}
-			}
+		}
+		_, optional := imageLayoutFiles(imageName, ext, func(name string) bool {
+			common.LogTxtHandler().Info("Checking for existance of file: " + name)
+			statusCode, _ := operations.GetArtifact(downloadPath + name)
+			return statusCode == "200"
+		})
+		for _, name := range optional {
+			common.LogTxtHandler().Info("Downloading: " + downloadPath + name)
+			resultMsg, err = operations.RetrieveArtifact(downloadPath + name)
 			if err != nil {
-				common.LogTxtHandler().Error("Download OVF files: " + resultMsg) // Will contain "Error" with additional info
-			} else {
-				common.LogTxtHandler().Info("Download OVF files: " + resultMsg)  // "Completed file download"
+				common.LogTxtHandler().Error(resultMsg)
 			}
+		}
+		// We are ignoring any potential .scoreboard and .hlog files that may exist
+		// They are not necessary for the imaging process.
+		return "End of download process"
+	} else {
+		common.LogTxtHandler().Error("One or more required inputs have not been provided.")
+		common.LogTxtHandler().Error("DOWNLOAD URI: " + downloadUri)
+		common.LogTxtHandler().Error("OUTPUT DIRECTORY: " + outputDir)
+		return "Missing required inputs"
+	}
+}
+// PropQuery selects the artifacts for a bulk property update
+// Name and Props can be combined (artifacts matching the name that also have ALL of the properties); Aql is used on its own
+// Scope limits the Name and Props searches to certain repos and a path within them
+type PropQuery struct {
+	Name		string
+	Props		[]string
+	Aql			string
+	Scope		search.SearchScope
+}
+
+func BulkUpdateProps(serverApi, token string, query PropQuery, mutations []operations.PropMutation, dryRun bool) ([]operations.PropMutationResult, error) {
+	// Finds every artifact matching the query and applies the property changes to each of them
+	// Run with dryRun set to true first to preview which artifacts would be affected and how
+	util.ServerApi = serverApi
+	util.Token     = token
+	var listArtifacts []string
+	var err error
 
-			// We want the downloadList to fully complete before moving on, but if there were any errors,
-			// we won't try to download the disk files.
+	common.LogTxtHandler().Debug(">>> BULK UPDATING ARTIFACT PROPERTIES...")
+
+	if query.Aql != "" {
+		common.LogTxtHandler().Debug("Getting artifacts by AQL query...")
+		listArtifacts, err = search.GetArtifactsByAql(query.Aql)
+	} else if query.Name != "" {
+		common.LogTxtHandler().Debug("Getting artifacts by name...")
+		listArtifacts, err = search.GetArtifactsByName(query.Name, query.Scope)
+		if err == nil && len(query.Props) != 0 {
+			common.LogTxtHandler().Debug("Filtering list of artifacts by properties...")
+			var details []operations.ArtifactDetails
+			details, err = operations.GetArtifactDetailsForList(listArtifacts)
 			if err == nil {
-				// Download Disk File(s)
-				for i := 1; i < 15; i++ {   // allowing possibility of up to 15 disk files
-					strI = strconv.Itoa(i)
-					checkFile := imageName + "-disk" + strI + ".vmdk"					// changing from -disk-# to -disk#
-					common.LogTxtHandler().Info("Checking for existance of disk file: " + checkFile)
-
-					statusCode, err := operations.GetArtifact(downloadPath + checkFile)
-					if statusCode == "200" {
-						// If we found the artifact, download it...
-						common.LogTxtHandler().Info("Disk file FOUND. Downloading...")
-						resultMsg, err = operations.RetrieveArtifact(downloadPath + checkFile)
-						if err != nil {
-							common.LogTxtHandler().Error(resultMsg) // Will contain "Error" with additional info
-						}
-					} else {
-						common.LogTxtHandler().Info("Disk file doesn't exist. Reached end of disk files.")
-						common.LogTxtHandler().Info("End of OVF disk file checks.")
-						break
-					}
+				details, _ = operations.FilterDetailsByProps(details, query.Props)
+				listArtifacts = nil
+				for _, d := range details {
+					listArtifacts = append(listArtifacts, d.Uri)
 				}
-			} else {
-				common.LogTxtHandler().Error("Errors encountered. The remainder of the file download process will terminate.")
 			}
-		} else if ext == ".vmtx" {
-			// Download known, static VMTX files
-			common.LogTxtHandler().Info("Image type identified as VMTX. Downloading VMTX files...")
-			downloadList = []string{".nvram", ".vmsd", ".vmtx", ".vmxf"}
-			for _, item := range downloadList {
-				artifactPath = downloadPath + imageName + item // builds URI path for each expected file type
-				common.LogTxtHandler().Info("Downloading: " + artifactPath)
-
-				resultMsg, err = operations.RetrieveArtifact(artifactPath)
-				if err != nil {
-					common.LogTxtHandler().Error("Error downloading " + artifactPath)
-					common.LogTxtHandler().Error(resultMsg)
-					break
-				}
+		}
+	} else if len(query.Props) != 0 {
+		common.LogTxtHandler().Debug("Getting artifacts by properties...")
+		listArtifacts, err = search.GetArtifactsByProps(query.Props, query.Scope)
+	} else {
+		err = errors.New("Unable to update properties without an artifact name, one or more properties, or an AQL query.")
+	}
+
+	if err != nil {
+		strErr := fmt.Sprintf("%v\n", err)
+		common.LogTxtHandler().Error("Error getting list of matching artifacts - " + strErr)
+		return nil, err
+	}
+	if len(listArtifacts) == 0 {
+		err = errors.New("No matching artifacts were found.")
+		common.LogTxtHandler().Error("No matching artifacts were found.")
+		return nil, err
+	}
+
+	return operations.ApplyPropMutations(listArtifacts, mutations, dryRun)
+}
+
+// Properties recorded on an image's files when it's promoted
+const (
+	PropRelease			= "release"
+	PropPromotedBy		= "promoted_by"
+	PropPromotedAt		= "promoted_at"
+	PropPromotedFrom	= "promoted_from"
+	PropPromotedTo		= "promoted_to"
+	PropPromotionStatus	= "promotion_status"
+)
+
+var ErrPromotionRejected = errors.New("Image failed promotion checks")
+
+// ImagePromotion describes which image to promote and where to
+// The image is found the same way as GetImageDetails (Name, Ext, Props, Scope); its whole folder is promoted
+type ImagePromotion struct {
+	Name			string
+	Ext				string
+	Props			[]string
+	Scope			search.SearchScope
+	TargetRepo		string
+	TargetPath		string		// Folder within the target repo to place the image folder in; defaults to the same path as the source
+	Move			bool		// Moves rather than copies the image folder
+	Release			string		// Value for the 'release' property on the target (ex: 'stable'); left unchanged if empty
+	PromotedBy		string
+	RequiredProps	[]string	// Properties ('key' or 'key=value') the image must have to be promoted
+	RequireManifest	bool		// Requires an OVF manifest (.mf) in the image folder; any manifest present is always verified
+	DryRun			bool
+}
+
+// PromotionResult is the outcome of PromoteImage
+// Status is one of "Planned" (dry run), "Promoted", "Rejected" (failed checks), or "Failed"
+type PromotionResult struct {
+	ArtifactUri		string
+	Source			string
+	Target			string
+	Problems		[]string
+	Transfer		operations.TransferResult
+	Status			string
+}
+
+func PromoteImage(serverApi, token string, promotion ImagePromotion) (PromotionResult, error) {
+	// Promotes an image (ex: from a 'test' repo to a 'stable' repo) by copying or moving its whole folder within Artifactory
+	// The image must first pass checks: a folder holding only this image, required properties, checksums on every file, a verified manifest,
+	// and nothing already at the target
+	// The outcome is recorded as properties on the files at the source (if copied or rejected) and the target
+	util.ServerApi = serverApi
+	util.Token     = token
+	var result PromotionResult
+
+	common.LogTxtHandler().Debug(">>> PROMOTING IMAGE...")
+
+	if promotion.TargetRepo == "" {
+		err := errors.New("Unable to promote an image without a target repo.")
+		common.LogTxtHandler().Error("Unable to promote an image without a target repo.")
+		return result, err
+	}
+	if promotion.PromotedBy == "" {
+		promotion.PromotedBy = "unknown"
+	}
+	promotedAt := time.Now().UTC().Format(time.RFC3339)
+	// Release and promoted by are property values, which are escaped when set (ex: e-mail addresses, 'ci/jenkins'),
+	// but the target repo has to be a plain repo key
+	if common.ContainsSpecialChars([]string{promotion.TargetRepo}) {
+		err := errors.New("Target repo '" + promotion.TargetRepo + "' isn't a valid repo key; it can't contain special characters --> )( }{ ][ *+^$\\/~`!@#%&<>;, and SPACE")
+		common.LogTxtHandler().Error("Target repo '" + promotion.TargetRepo + "' isn't a valid repo key; it can't contain special characters --> )( }{ ][ *+^$\\/~`!@#%&<>;, and SPACE")
+		return result, err
+	}
+
+	common.LogTxtHandler().Debug("Finding image...")
+	artifactUri, _, _, _, err := GetImageDetails(serverApi, token, promotion.Name, promotion.Ext, promotion.Props, promotion.Scope)
+	if err != nil {
+		return result, err
+	}
+	if artifactUri == "" {
+		err := errors.New("No image matching the criteria was found.")
+		common.LogTxtHandler().Error("No image matching the criteria was found.")
+		return result, err
+	}
+	result.ArtifactUri = artifactUri
+
+	repo, folder, _ := common.ParseArtifUriForRepoPathName("", artifactUri)
+	if folder == "." {
+		err := errors.New("Image must be in a folder to be promoted: " + artifactUri)
+		common.LogTxtHandler().Error("Image must be in a folder to be promoted: " + artifactUri)
+		return result, err
+	}
+	targetFolder := folder
+	if promotion.TargetPath != "" {
+		targetFolder = strings.Trim(promotion.TargetPath, "/") + "/" + path.Base(folder)
+	}
+	result.Source = "/" + repo + "/" + folder
+	result.Target = "/" + promotion.TargetRepo + "/" + targetFolder
+	common.LogTxtHandler().Info("Promoting: " + result.Source + " to " + result.Target)
+
+	common.LogTxtHandler().Debug("Checking image can be promoted...")
+	sourceFiles, problems, err := checkPromotion(artifactUri, repo, folder, promotion.TargetRepo, targetFolder, promotion)
+	if err != nil {
+		result.Status = "Failed"
+		return result, err
+	}
+	result.Problems = problems
+	if len(problems) != 0 {
+		result.Status = "Rejected"
+		for _, p := range problems {
+			common.LogTxtHandler().Error("PROMOTION CHECK FAILED: " + p)
+		}
+		err = fmt.Errorf("%w: %s", ErrPromotionRejected, strings.Join(problems, "; "))
+		if !promotion.DryRun {
+			err = errors.Join(err, recordPromotion(sourceFiles, []string{PropPromotionStatus + "=rejected", PropPromotedAt + "=" + promotedAt}))
+		}
+		return result, err
+	}
+
+	opts := operations.TransferOptions{DryRun: promotion.DryRun, FailFast: true}
+	if promotion.Move {
+		result.Transfer, err = operations.MoveItem(result.Source, result.Target, opts)
+	} else {
+		result.Transfer, err = operations.CopyItem(result.Source, result.Target, opts)
+	}
+	if err != nil {
+		result.Status = "Failed"
+		if !promotion.DryRun && !promotion.Move {
+			err = errors.Join(err, recordPromotion(sourceFiles, []string{PropPromotionStatus + "=failed", PropPromotedAt + "=" + promotedAt}))
+		}
+		return result, err
+	}
+	if promotion.DryRun {
+		result.Status = "Planned"
+		return result, nil
+	}
+
+	targetProps := []string{PropPromotionStatus + "=promoted", PropPromotedFrom + "=" + repo, PropPromotedBy + "=" + promotion.PromotedBy, PropPromotedAt + "=" + promotedAt}
+	if promotion.Release != "" {
+		targetProps = append(targetProps, PropRelease + "=" + promotion.Release)
+	}
+	// The image folder was transferred as a whole, so each source file has the same place under the target
+	storageUri := common.TrimEndSlashUrl(util.ServerApi) + "/storage"
+	var targetFiles []string
+	for _, artifUri := range sourceFiles {
+		targetFiles = append(targetFiles, storageUri + result.Target + strings.TrimPrefix(artifUri, storageUri + result.Source))
+	}
+	err = recordPromotion(targetFiles, targetProps)
+	if err == nil && !promotion.Move {
+		err = recordPromotion(sourceFiles, []string{PropPromotionStatus + "=promoted", PropPromotedTo + "=" + promotion.TargetRepo, PropPromotedBy + "=" + promotion.PromotedBy, PropPromotedAt + "=" + promotedAt})
+	}
+	if err != nil {
+		// The image was promoted; only recording it failed
+		result.Status = "Promoted"
+		return result, err
+	}
+
+	result.Status = "Promoted"
+	common.LogTxtHandler().Info("Image promoted to: " + result.Target)
+	return result, nil
+}
+
+func checkPromotion(artifactUri, repo, folder, targetRepo, targetFolder string, promotion ImagePromotion) ([]string, []string, error) {
+	// Returns the URIs of the files in the image folder and a list of any checks that failed
+	var problems []string
+
+	if len(promotion.RequiredProps) != 0 {
+		details, err := operations.GetArtifactDetailsForList([]string{artifactUri})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, kv := range promotion.RequiredProps {
+			if !details[0].HasProp(kv) {
+				problems = append(problems, "image is missing required property '" + kv + "'")
 			}
+		}
+	}
+
+	// Every file in the image folder (and any subfolders) must have checksums
+	q := search.ItemsFind(search.Eq("repo", repo), search.Eq("type", "file"), search.Or(search.Eq("path", folder), search.Match("path", folder + "/*"))).
+		Include("repo", "path", "name", "type", "size", "actual_sha1", "actual_md5", "sha256")
+	items, _, err := search.ExecuteAql(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The folder is promoted as a whole, so it must hold only this image's files
+	_, _, fileName := common.ParseArtifUriForRepoPathName("", artifactUri)
+	inFolder := make(map[string]bool)
+	for _, item := range items {
+		if item.Path == folder {
+			inFolder[item.Name] = true
+		}
+	}
+	imageName := common.ParseFilenameForImageName(fileName)
+	required, optional := imageLayoutFiles(imageName, strings.ToLower(filepath.Ext(fileName)), func(name string) bool { return inFolder[name] })
+	imageFiles := append(required, optional...)
+
+	var sourceFiles []string
+	var manifests []search.Item
+	for _, item := range items {
+		sourceFiles = append(sourceFiles, item.Uri())
+		if item.Path != folder || !slices.Contains(imageFiles, item.Name) {
+			problems = append(problems, "file '" + strings.TrimPrefix(item.Path + "/" + item.Name, folder + "/") + "' in the image folder isn't part of image '" + imageName + "'")
+		}
+		if item.Checksums.Sha1 == "" || item.Checksums.Sha256 == "" {
+			problems = append(problems, "file '" + item.Name + "' is missing a SHA1 or SHA256 checksum")
+		}
+		if strings.EqualFold(path.Ext(item.Name), ".mf") {
+			manifests = append(manifests, item)
+		}
+	}
+
+	if len(manifests) == 0 && promotion.RequireManifest {
+		problems = append(problems, "image folder has no manifest (.mf)")
+	}
+	for _, manifest := range manifests {
+		manifestProblems, err := verifyManifest(manifest, items)
+		if err != nil {
+			return nil, nil, err
+		}
+		problems = append(problems, manifestProblems...)
+	}
+
+	// Promoting on top of an existing folder would merge two images
+	_, status, err := common.SendRequestNoCache("GET", util.ServerApi + "/storage/" + targetRepo + "/" + targetFolder, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	switch status {
+	case http.StatusOK:
+		problems = append(problems, "target '/" + targetRepo + "/" + targetFolder + "' already exists")
+	case http.StatusNotFound:
+	default:
+		err := errors.New("Unable to check whether target '/" + targetRepo + "/" + targetFolder + "' exists (status code " + strconv.Itoa(status) + ").")
+		common.LogTxtHandler().Error("Unable to check whether target '/" + targetRepo + "/" + targetFolder + "' exists (status code " + strconv.Itoa(status) + ").")
+		return nil, nil, err
+	}
+	return sourceFiles, problems, nil
+}
+
+func verifyManifest(manifest search.Item, items []search.Item) ([]string, error) {
+	// Checks each entry of an OVF manifest (ex: 'SHA256(win2022.ovf)= 3a7b...') against the checksums Artifactory has for the file
+	var problems []string
+
+	body, status, err := common.SendRequest("GET", manifest.DownloadUri, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		err := errors.New("Unable to download manifest: " + manifest.Name + " (status code " + strconv.Itoa(status) + ")")
+		common.LogTxtHandler().Error("Unable to download manifest: " + manifest.Name + " (status code " + strconv.Itoa(status) + ")")
+		return nil, err
+	}
+
+	byName := make(map[string]search.Item)
+	for _, item := range items {
+		if item.Path == manifest.Path {
+			byName[item.Name] = item
+		}
+	}
+
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		entry, expected, found := strings.Cut(line, "=")
+		algorithm, fileName, hasName := strings.Cut(entry, "(")
+		if !found || !hasName {
+			problems = append(problems, "manifest '" + manifest.Name + "' has an unreadable line: " + line)
+			continue
+		}
+		fileName = strings.TrimSuffix(strings.TrimSpace(fileName), ")")
+		expected = strings.ToLower(strings.TrimSpace(expected))
+
+		item, exists := byName[fileName]
+		if !exists {
+			problems = append(problems, "file '" + fileName + "' listed in manifest '" + manifest.Name + "' is missing")
+			continue
+		}
+		var actual string
+		switch strings.ToUpper(strings.TrimSpace(algorithm)) {
+		case "SHA256":
+			actual = item.Checksums.Sha256
+		case "SHA1":
+			actual = item.Checksums.Sha1
+		default:
+			problems = append(problems, "manifest '" + manifest.Name + "' uses unsupported algorithm '" + algorithm + "'")
+			continue
+		}
+		if actual != expected {
+			problems = append(problems, "file '" + fileName + "' doesn't match manifest '" + manifest.Name + "'")
+		}
+	}
+	return problems, nil
+}
+
+func recordPromotion(listArtifUris, kvProps []string) error {
+	// Sets the promotion properties on every file; failures are logged and returned, but don't undo the promotion
+	if len(listArtifUris) == 0 {
+		return nil
+	}
+	var mutations []operations.PropMutation
+	for _, kv := range kvProps {
+		key, value, _ := strings.Cut(kv, "=")
+		mutations = append(mutations, operations.PropMutation{Action: operations.PropSet, Key: key, Value: value})
+	}
+	results, err := operations.ApplyPropMutations(listArtifUris, mutations, false)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			strErr := fmt.Sprintf("%v\n", r.Err)
+			common.LogTxtHandler().Error("Unable to record promotion on: " + r.Uri + " - " + strErr)
+			return r.Err
+		}
+	}
+	return nil
+}
+
+// ImageDeletion describes which image to delete
+// The image is given by the download URI of its main file (.ova, .ovf, or .vmtx), or found the same way as GetImageDetails (Name, Ext, Props, Scope)
+type ImageDeletion struct {
+	DownloadUri		string
+	Name			string
+	Ext				string
+	Props			[]string
+	Scope			search.SearchScope
+	WholeFolder		bool		// Deletes the image's folder, including any files that aren't part of the image, rather than just the image's files
+	DryRun			bool
+}
+
+// ImageDeletionResult is the outcome of DeleteImage, with one entry per file
+// Status is one of "Planned" (dry run), "Success", or "Failed"
+type ImageDeletionResult struct {
+	Folder			string
+	Image			string
+	Files			[]ImageFileResult
+	Status			string
+}
+
+type ImageFileResult struct {
+	Path			string
+	Status			string
+	Err				error
+}
+
+func DeleteImage(serverApi, token string, deletion ImageDeletion) (ImageDeletionResult, error) {
+	// Deletes every file belonging to an image, found with the same layout rules used by DownloadArtifacts, or the image's whole folder
+	// Each file that would be (or was) deleted is listed in the result; with DryRun nothing is deleted
+	util.ServerApi = serverApi
+	util.Token     = token
+	var result ImageDeletionResult
+
+	common.LogTxtHandler().Info(">>> DELETING IMAGE...")
+
+	var artifactUri string
+	if deletion.DownloadUri != "" {
+		artifactUri = common.SetArtifUriFromDownloadUri(deletion.DownloadUri)
+	} else {
+		var err error
+		artifactUri, _, _, _, err = GetImageDetails(serverApi, token, deletion.Name, deletion.Ext, deletion.Props, deletion.Scope)
+		if err != nil {
+			return result, err
+		}
+		if artifactUri == "" {
+			err := errors.New("No image matching the criteria was found.")
+			common.LogTxtHandler().Error("No image matching the criteria was found.")
+			return result, err
+		}
+	}
+
+	repo, folder, fileName := common.ParseArtifUriForRepoPathName("", artifactUri)
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if ext != ".ova" && ext != ".ovf" && ext != ".vmtx" {
+		err := errors.New("Image must be identified by its OVA, OVF, or VMTX file: " + fileName)
+		common.LogTxtHandler().Error("Image must be identified by its OVA, OVF, or VMTX file: " + fileName)
+		return result, err
+	}
+	result.Image = fileName
+	result.Folder = "/" + repo
+	if folder != "." {
+		result.Folder = result.Folder + "/" + folder
+	}
+
+	// One listing of the folder is enough to check which of the image's expected files exist
+	q := search.ItemsFind(search.Eq("repo", repo), search.Eq("path", folder), search.Eq("type", "file")).Include("repo", "path", "name")
+	items, _, err := search.ExecuteAql(q)
+	if err != nil {
+		return result, err
+	}
+	var folderFiles []string
+	existing := make(map[string]bool)
+	for _, item := range items {
+		folderFiles = append(folderFiles, item.Name)
+		existing[item.Name] = true
+	}
+	if !existing[fileName] {
+		err := errors.New("Image file not found: " + result.Folder + "/" + fileName)
+		common.LogTxtHandler().Error("Image file not found: " + result.Folder + "/" + fileName)
+		return result, err
+	}
+
+	imageName := common.ParseFilenameForImageName(fileName)
+	required, optional := imageLayoutFiles(imageName, ext, func(name string) bool { return existing[name] })
+	imageFiles := slices.DeleteFunc(append(required, optional...), func(name string) bool { return !existing[name] })
+
+	if deletion.WholeFolder {
+		if folder == "." {
+			err := errors.New("Image is at the root of repo '" + repo + "'; deleting the whole folder would delete the repo's contents.")
+			common.LogTxtHandler().Error("Image is at the root of repo '" + repo + "'; deleting the whole folder would delete the repo's contents.")
+			return result, err
+		}
+		for _, name := range folderFiles {
+			if !slices.Contains(imageFiles, name) {
+				common.LogTxtHandler().Warn("File isn't part of the image but will be deleted with its folder: " + name)
+			}
+		}
+		imageFiles = folderFiles
+	}
+	for _, name := range imageFiles {
+		result.Files = append(result.Files, ImageFileResult{Path: result.Folder + "/" + name, Status: "Planned"})
+	}
+
+	if deletion.DryRun {
+		for _, f := range result.Files {
+			common.LogTxtHandler().Info("WOULD DELETE: " + f.Path)
+		}
+		result.Status = "Planned"
+		return result, nil
+	}
+
+	var failed int
+	if deletion.WholeFolder {
+		_, err := operations.DeleteItem(result.Folder)
+		for idx := range result.Files {
+			result.Files[idx].Status, result.Files[idx].Err = "Success", err
 			if err != nil {
-				common.LogTxtHandler().Error("Download VMTX files: " + resultMsg) // Will contain "Error" with additional info
-			} else {
-				common.LogTxtHandler().Info("Download VMTX files: " + resultMsg)  // "Completed file download"
+				result.Files[idx].Status = "Failed"
+				failed++
+			}
+		}
+	} else {
+		for idx := range result.Files {
+			_, err := operations.DeleteItem(result.Files[idx].Path)
+			if err != nil {
+				result.Files[idx].Status, result.Files[idx].Err = "Failed", err
+				failed++
+				continue
 			}
+			result.Files[idx].Status = "Success"
+		}
+	}
+	for _, f := range result.Files {
+		common.LogTxtHandler().Info("DELETE " + strings.ToUpper(f.Status) + ": " + f.Path)
+	}
 
-			// We want the downloadList to fully complete before moving on, but if there were any errors,
-			// we won't try to download the disk files.
-			if err == nil {
-				// Download Disk File(s)
-				checkFile := imageName + ".vmdk"
-				common.LogTxtHandler().Info("Checking for existance of disk file: " + checkFile)
-				task = "Unnumbered virtual disk file check"
-				resultMsg, err = operations.CheckFileAndDownload(checkFile, downloadPath, task)
-				if err != nil {
-					common.LogTxtHandler().Error(resultMsg)
-				}
-				
-				// Loop for virtual disk files ----------------------------->
-				extString = ".vmdk"
-				task      = "Numbered virtual disk file check"
-				resultMsg, err = operations.CheckFileLoopAndDownload(imageName, downloadPath, extString, task)
-				if err != nil {
-					common.LogTxtHandler().Error(resultMsg)
-				}
-				
-				// Loop for disk -ctk files ----------------------------->
-				checkFile = imageName + "-ctk.vmdk"
-				common.LogTxtHandler().Info("Checking for existance of disk file: " + checkFile)
-				task = "Unnumbered virtual ctk disk file check"
-				resultMsg, err = operations.CheckFileAndDownload(checkFile, downloadPath, task)
-				if err != nil {
-					common.LogTxtHandler().Error(resultMsg)
-				}
-				
-				extString = "-ctk.vmdk"
-				task      = "Numbered virtual ctk disk file check"
-				resultMsg, err = operations.CheckFileLoopAndDownload(imageName, downloadPath, extString, task)
-				if err != nil {
-					common.LogTxtHandler().Error(resultMsg)
-				}
-				
-				// Loop for VM data disk (-flat) files ----------------------------->
-				checkFile = imageName + "-flat.vmdk"
-				common.LogTxtHandler().Info("Checking for existance of disk file: " + checkFile)
-				task = "Unnumbered virtual data disk file check"
-				resultMsg, err = operations.CheckFileAndDownload(checkFile, downloadPath, task)
-				if err != nil {
-					common.LogTxtHandler().Error(resultMsg)
-				}
-				
-				extString = "-flat.vmdk"
-				task      = "Numbered virtual data disk file check"
-				resultMsg, err = operations.CheckFileLoopAndDownload(imageName, downloadPath, extString, task)
-				if err != nil {
-					common.LogTxtHandler().Error(resultMsg)
-				}
-				
-				// Download associated vmware.log, if it exists  -------------------->
-				checkFile = "vmware.log"
-				common.LogTxtHandler().Info("Checking for existance of file: " + checkFile)
-				task = "vmware.log file check"
-				resultMsg, err = operations.CheckFileAndDownload(checkFile, downloadPath, task)
-				if err != nil {
-					common.LogTxtHandler().Error(resultMsg)
+	if failed != 0 {
+		result.Status = "Failed"
+		err := errors.New("Unable to delete " + strconv.Itoa(failed) + " of " + strconv.Itoa(len(result.Files)) + " image files.")
+		common.LogTxtHandler().Error("Unable to delete " + strconv.Itoa(failed) + " of " + strconv.Itoa(len(result.Files)) + " image files.")
+		return result, err
+	}
+	result.Status = "Success"
+	common.LogTxtHandler().Info("Image deleted: " + result.Folder + "/" + fileName)
+	return result, nil
+}
+
+func imageLayoutFiles(imageName, ext string, exists func(name string) bool) ([]string, []string) {
+	// Returns the names of the files that make up an image; used by both DownloadArtifacts and DeleteImage
+		// OVA  - required: imageName.ova
+		// OVF  - required: imageName.ovf and .mf; optional: imageName-disk1.vmdk, -disk2.vmdk, ... until one is missing
+		// VMTX - required: imageName .nvram/.vmsd/.vmtx/.vmxf; optional: .vmdk/-ctk.vmdk/-flat.vmdk with their _1, _2, ...
+		//        numbered disks, and vmware.log
+	// Required files are always returned; exists is only called for optional files, which are returned if they exist
+	// Numbered disks stop at the first missing number (up to 14)
+	var required, optional []string
+	addIfExists := func(name string) bool {
+		if exists(name) {
+			optional = append(optional, name)
+			return true
+		}
+		return false
+	}
+
+	switch ext {
+	case ".ova":
+		required = []string{imageName + ".ova"}
+	case ".ovf":
+		required = []string{imageName + ".ovf", imageName + ".mf"}
+		for i := 1; i < 15; i++ {
+			if !addIfExists(imageName + "-disk" + strconv.Itoa(i) + ".vmdk") {
+				break
+			}
+		}
+	case ".vmtx":
+		for _, item := range []string{".nvram", ".vmsd", ".vmtx", ".vmxf"} {
+			required = append(required, imageName + item)
+		}
+		for _, extString := range []string{".vmdk", "-ctk.vmdk", "-flat.vmdk"} {
+			addIfExists(imageName + extString)
+			for i := 1; i < 15; i++ {
+				if !addIfExists(imageName + "_" + strconv.Itoa(i) + extString) {
+					break
 				}
-			} else {
-				common.LogTxtHandler().Error("Errors encountered. The remainder of the file download process will terminate.")
-				return "File download failed"
 			}
-			// We are ignoring any potential .scoreboard and .hlog files that may exist
-			// They are not necessary for the imaging process.
 		}
-		return "End of download process"
-	} else {
-		common.LogTxtHandler().Error("One or more required inputs have not been provided.")
-		common.LogTxtHandler().Error("DOWNLOAD URI: " + downloadUri)
-		common.LogTxtHandler().Error("OUTPUT DIRECTORY: " + outputDir)
-		return "Missing required inputs"
+		addIfExists("vmware.log")
 	}
-}
\ No newline at end of file
+	return required, optional
+}
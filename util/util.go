@@ -5,5 +5,7 @@ var Token string
 var ServerApi string
 var Logging string
 var OutputDir string
+var SkipIdentical bool    // If true, uploads are skipped when identical content already exists at the target
+var MaxWorkers int = 8   // Upper bound on concurrent API requests for batch operations
 
 func init() {}
\ No newline at end of file